@@ -0,0 +1,128 @@
+// Package aiff reads and writes the ID3v2 tag embedded in the "ID3 " chunk
+// of an AIFF or AIFF-C file, and reports the audio properties of its COMM
+// chunk.
+package aiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/lsongdev/id3-go/internal/iff"
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+// chunkIDs are the IDs of the chunk holding the tag, the first one being
+// used for new tags.
+var chunkIDs = []string{"ID3 ", "id3 "}
+
+// Properties are the audio properties given by the COMM chunk. Compression
+// is the compression type of AIFF-C files, such as "NONE" or "sowt", and
+// empty for AIFF.
+type Properties struct {
+	Channels     int
+	SampleFrames uint32
+	BitDepth     int
+	SampleRate   float64
+	Duration     time.Duration
+	Compression  string
+}
+
+func readContainer(r io.ReadSeeker) (*iff.Container, error) {
+	c, err := iff.Read(r, binary.BigEndian, "FORM")
+	if err != nil {
+		return nil, err
+	}
+	if c.Form != "AIFF" && c.Form != "AIFC" {
+		return nil, fmt.Errorf("invalid AIFF form type %q", c.Form)
+	}
+	return c, nil
+}
+
+// Read returns the ID3v2 tag of an AIFF file, or nil when it has none.
+func Read(r io.ReadSeeker) (*v2.ID3v2Tag, error) {
+	c, err := readContainer(r)
+	if err != nil {
+		return nil, err
+	}
+	chunk, ok := c.Find(chunkIDs...)
+	if !ok {
+		return nil, nil
+	}
+	data, err := c.ReadChunk(r, chunk)
+	if err != nil {
+		return nil, err
+	}
+	return v2.Read(bytes.NewReader(data))
+}
+
+// Write copies the AIFF file from r to w with its ID3v2 tag replaced by tag,
+// or removed when tag is nil. The tag chunk is added at the end of the file
+// when there was none, and the FORM size is updated.
+func Write(w io.Writer, r io.ReadSeeker, tag *v2.ID3v2Tag) error {
+	c, err := readContainer(r)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if tag != nil {
+		if data, err = tag.Bytes(); err != nil {
+			return err
+		}
+	}
+	return c.Replace(w, r, chunkIDs, data)
+}
+
+// ReadProperties returns the audio properties of an AIFF file.
+func ReadProperties(r io.ReadSeeker) (*Properties, error) {
+	c, err := readContainer(r)
+	if err != nil {
+		return nil, err
+	}
+	chunk, ok := c.Find("COMM")
+	if !ok {
+		return nil, fmt.Errorf("missing AIFF COMM chunk")
+	}
+	data, err := c.ReadChunk(r, chunk)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 18 {
+		return nil, fmt.Errorf("AIFF COMM chunk too short")
+	}
+	p := &Properties{
+		Channels:     int(binary.BigEndian.Uint16(data[0:2])),
+		SampleFrames: binary.BigEndian.Uint32(data[2:6]),
+		BitDepth:     int(binary.BigEndian.Uint16(data[6:8])),
+		SampleRate:   parseExtended(data[8:18]),
+	}
+	if c.Form == "AIFC" && len(data) >= 22 {
+		p.Compression = string(data[18:22])
+	}
+	if p.SampleRate > 0 {
+		p.Duration = time.Duration(float64(p.SampleFrames) / p.SampleRate * float64(time.Second))
+	}
+	return p, nil
+}
+
+// parseExtended decodes an 80-bit IEEE 754 extended precision number: a
+// sign bit, a 15-bit exponent biased by 16383 and a 64-bit mantissa with
+// an explicit integer bit.
+func parseExtended(data []byte) float64 {
+	exponent := int(binary.BigEndian.Uint16(data[0:2]) & 0x7FFF)
+	mantissa := binary.BigEndian.Uint64(data[2:10])
+	if exponent == 0 && mantissa == 0 {
+		return 0
+	}
+	if exponent == 0x7FFF {
+		return math.Inf(1)
+	}
+	f := math.Ldexp(float64(mantissa), exponent-16383-63)
+	if data[0]&0x80 != 0 {
+		f = -f
+	}
+	return f
+}
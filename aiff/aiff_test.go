@@ -0,0 +1,88 @@
+package aiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+// sampleRate44100 is 44100 as an 80-bit extended precision number.
+var sampleRate44100 = []byte{0x40, 0x0E, 0xAC, 0x44, 0, 0, 0, 0, 0, 0}
+
+func testAIFF(form string, comm []byte) []byte {
+	var body bytes.Buffer
+	body.WriteString(form)
+	body.WriteString("COMM")
+	binary.Write(&body, binary.BigEndian, uint32(len(comm)))
+	body.Write(comm)
+	if len(comm)%2 == 1 {
+		body.WriteByte(0)
+	}
+	body.WriteString("SSND\x00\x00\x00\x09")
+	body.Write(make([]byte, 10))
+	file := append([]byte("FORM\x00\x00\x00\x00"), body.Bytes()...)
+	binary.BigEndian.PutUint32(file[4:], uint32(body.Len()))
+	return file
+}
+
+func TestReadProperties(t *testing.T) {
+	comm := []byte{0, 2, 0, 0x01, 0x58, 0x88, 0, 16}
+	comm = append(comm, sampleRate44100...)
+	p, err := ReadProperties(bytes.NewReader(testAIFF("AIFF", comm)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Properties{Channels: 2, SampleFrames: 88200, BitDepth: 16, SampleRate: 44100, Duration: 2 * time.Second}
+	if *p != want {
+		t.Errorf("got %+v, want %+v", *p, want)
+	}
+
+	// AIFF-C adds the compression type and name, here of odd length.
+	comm = append(comm, "sowt\x0dlittle endian"...)
+	p, err = ReadProperties(bytes.NewReader(testAIFF("AIFC", comm)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Compression != "sowt" || p.SampleRate != 44100 {
+		t.Errorf("got %+v", *p)
+	}
+
+	if _, err := ReadProperties(bytes.NewReader(testAIFF("WAVE", comm))); err == nil {
+		t.Error("WAVE form read as AIFF")
+	}
+	if _, err := ReadProperties(bytes.NewReader(testAIFF("AIFF", comm[:10]))); err == nil {
+		t.Error("short COMM chunk read")
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	comm := append([]byte{0, 1, 0, 0, 0, 10, 0, 8}, sampleRate44100...)
+	file := testAIFF("AIFF", comm)
+	tag := v2.NewID3v2Tag(4)
+	tag.SetTextValues("TIT2", "Title")
+	var buf bytes.Buffer
+	if err := Write(&buf, bytes.NewReader(file), tag); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read == nil || read.Get("title") != "Title" {
+		t.Errorf("read %v", read)
+	}
+	if size := binary.BigEndian.Uint32(buf.Bytes()[4:]); int(size) != buf.Len()-8 {
+		t.Errorf("FORM size %d for %d bytes", size, buf.Len())
+	}
+
+	var removed bytes.Buffer
+	if err := Write(&removed, bytes.NewReader(buf.Bytes()), nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(removed.Bytes(), file) {
+		t.Errorf("removing the tag gave %q", removed.Bytes())
+	}
+}
@@ -0,0 +1,187 @@
+// Package chapters converts the chapters stored in an ID3v2 tag as CHAP and
+// CTOC frames to and from the chapter formats used by podcast tooling:
+// Podcasting 2.0 JSON, FFmpeg metadata, Podlove Simple Chapters and CUE
+// sheets.
+package chapters
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+// Chapter is a chapter independent of the format storing it. End is zero
+// when unknown. ImageURL refers to an image by URL, while Image holds an
+// embedded image.
+type Chapter struct {
+	ID        string
+	Start     time.Duration
+	End       time.Duration
+	Title     string
+	URL       string
+	ImageURL  string
+	Image     []byte
+	ImageMIME string
+}
+
+// tocElementID is the element ID of the table of contents written by ToTag.
+const tocElementID = "toc"
+
+// FromTag returns the chapters of a tag. They are ordered as listed by the
+// top-level table of contents when there is one, and by start time
+// otherwise.
+func FromTag(tag *v2.ID3v2Tag) []Chapter {
+	frames := tag.Chapters()
+	var chapters []Chapter
+	byID := make(map[string]Chapter)
+	for _, frame := range frames {
+		c := Chapter{
+			ID:    frame.ElementID,
+			Start: frame.StartTime,
+			End:   frame.EndTime,
+			Title: frame.Title(),
+		}
+		for _, sub := range frame.Frames {
+			switch data := sub.Data.(type) {
+			case *v2.UserURLFrame:
+				c.URL = data.URL
+			case *v2.ImageFrame:
+				if data.MIMEType == "-->" {
+					c.ImageURL = string(data.Data)
+				} else {
+					c.Image, c.ImageMIME = data.Data, data.MIMEType
+				}
+			}
+		}
+		chapters = append(chapters, c)
+		byID[c.ID] = c
+	}
+
+	for _, toc := range tag.TablesOfContents() {
+		if !toc.TopLevel || !toc.Ordered {
+			continue
+		}
+		var ordered []Chapter
+		for _, id := range toc.ChildElementIDs {
+			if c, ok := byID[id]; ok {
+				ordered = append(ordered, c)
+				delete(byID, id)
+			}
+		}
+		if len(byID) == 0 {
+			return ordered
+		}
+	}
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].Start < chapters[j].Start
+	})
+	return chapters
+}
+
+// ToTag replaces the chapters of a tag with CHAP frames and a top-level
+// ordered CTOC frame. Chapters without an end time end where the next one
+// starts, and the last one at length. Missing IDs are generated, skipping
+// those already used. The tag is left unchanged when an error is returned.
+func ToTag(tag *v2.ID3v2Tag, chapters []Chapter, length time.Duration) error {
+	if tag.Header.Version < 3 {
+		return fmt.Errorf("chapters are not supported by ID3v2.%d", tag.Header.Version)
+	}
+	chapters = withEndTimes(chapters, length)
+	used := map[string]bool{tocElementID: true}
+	for _, c := range chapters {
+		if c.ID == "" {
+			continue
+		}
+		if used[c.ID] {
+			return fmt.Errorf("duplicate chapter ID: %s", c.ID)
+		}
+		used[c.ID] = true
+	}
+
+	// The frames are built in a scratch tag, which checks them for
+	// conflicts and encodes them, before touching the tag.
+	scratch := v2.NewID3v2Tag(tag.Header.Version)
+	toc := &v2.TableOfContentsFrame{
+		ElementID: tocElementID,
+		TopLevel:  true,
+		Ordered:   true,
+	}
+	n := 0
+	for _, c := range chapters {
+		for c.ID == "" {
+			if id := fmt.Sprintf("chp%d", n); !used[id] {
+				c.ID = id
+				used[id] = true
+			}
+			n++
+		}
+		if err := scratch.AddFrame(&v2.ID3v2Frame{Id: "CHAP", Data: chapterFrame(c)}); err != nil {
+			return err
+		}
+		toc.ChildElementIDs = append(toc.ChildElementIDs, c.ID)
+	}
+	if len(chapters) > 0 {
+		if err := scratch.AddFrame(&v2.ID3v2Frame{Id: "CTOC", Data: toc}); err != nil {
+			return err
+		}
+	}
+	if _, err := scratch.Bytes(); err != nil {
+		return err
+	}
+
+	tag.RemoveFrames("CHAP")
+	tag.RemoveFrames("CTOC")
+	tag.Frames = append(tag.Frames, scratch.Frames...)
+	return nil
+}
+
+// chapterFrame creates the CHAP frame of a chapter.
+func chapterFrame(c Chapter) *v2.ChapterFrame {
+	frame := &v2.ChapterFrame{
+		ElementID:   c.ID,
+		StartTime:   c.Start,
+		EndTime:     c.End,
+		StartOffset: v2.ChapterOffsetUnused,
+		EndOffset:   v2.ChapterOffsetUnused,
+	}
+	if c.Title != "" {
+		frame.Frames = append(frame.Frames, &v2.ID3v2Frame{Id: "TIT2", Data: v2.NewTextFrame(c.Title)})
+	}
+	if c.URL != "" {
+		frame.Frames = append(frame.Frames, &v2.ID3v2Frame{Id: "WXXX", Data: &v2.UserURLFrame{URL: c.URL}})
+	}
+	if c.Image != nil {
+		frame.Frames = append(frame.Frames, &v2.ID3v2Frame{Id: "APIC", Data: &v2.ImageFrame{
+			MIMEType:    c.ImageMIME,
+			PictureType: v2.PictureTypeOther,
+			Data:        c.Image,
+		}})
+	} else if c.ImageURL != "" {
+		frame.Frames = append(frame.Frames, &v2.ID3v2Frame{Id: "APIC", Data: &v2.ImageFrame{
+			MIMEType:    "-->",
+			PictureType: v2.PictureTypeOther,
+			Data:        []byte(c.ImageURL),
+		}})
+	}
+	return frame
+}
+
+// withEndTimes returns a copy of chapters with missing end times filled in.
+func withEndTimes(chapters []Chapter, length time.Duration) []Chapter {
+	filled := append([]Chapter(nil), chapters...)
+	for i := range filled {
+		if filled[i].End != 0 {
+			continue
+		}
+		if i+1 < len(filled) {
+			filled[i].End = filled[i+1].Start
+		} else if length > filled[i].Start {
+			filled[i].End = length
+		} else {
+			filled[i].End = filled[i].Start
+		}
+	}
+	return filled
+}
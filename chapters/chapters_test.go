@@ -0,0 +1,158 @@
+package chapters
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+var testChapters = []Chapter{
+	{ID: "intro", Start: 0, End: 90 * time.Second, Title: "Intro", URL: "https://example.com/intro"},
+	{ID: "news", Start: 90 * time.Second, End: 5 * time.Minute, Title: "News; and more", ImageURL: "https://example.com/news.png"},
+	{ID: "outro", Start: 5 * time.Minute, End: 6 * time.Minute, Title: "Outro", Image: []byte{0xFF, 0xD8, 0xFF}, ImageMIME: "image/jpeg"},
+}
+
+func TestTagRoundTrip(t *testing.T) {
+	for _, version := range []int{3, 4} {
+		tag := v2.NewID3v2Tag(version)
+		if err := ToTag(tag, testChapters, 6*time.Minute); err != nil {
+			t.Fatal(err)
+		}
+		data, err := tag.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		read, err := v2.Read(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := FromTag(read); !reflect.DeepEqual(got, testChapters) {
+			t.Errorf("v2.%d: got %+v, want %+v", version, got, testChapters)
+		}
+	}
+}
+
+func TestToTagGeneratesIDs(t *testing.T) {
+	tag := v2.NewID3v2Tag(4)
+	chapters := []Chapter{
+		{Start: 0, Title: "A"},
+		{ID: "chp0", Start: time.Minute, Title: "B"},
+		{Start: 2 * time.Minute, Title: "C"},
+	}
+	if err := ToTag(tag, chapters, 3*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, c := range FromTag(tag) {
+		ids = append(ids, c.ID)
+	}
+	if want := []string{"chp1", "chp0", "chp2"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got IDs %q, want %q", ids, want)
+	}
+	got := FromTag(tag)
+	if got[0].End != time.Minute || got[2].End != 3*time.Minute {
+		t.Errorf("end times not filled in: %+v", got)
+	}
+}
+
+func TestToTagLeavesTagOnError(t *testing.T) {
+	tag := v2.NewID3v2Tag(4)
+	if err := ToTag(tag, testChapters, 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, chapters := range [][]Chapter{
+		{{ID: "a"}, {ID: "a"}},
+		{{ID: tocElementID}},
+	} {
+		if err := ToTag(tag, chapters, 0); err == nil {
+			t.Errorf("%+v: no error", chapters)
+		}
+		if got := FromTag(tag); !reflect.DeepEqual(got, testChapters) {
+			t.Errorf("%+v: tag changed to %+v", chapters, got)
+		}
+	}
+	if err := ToTag(v2.NewID3v2Tag(2), testChapters, 0); err == nil {
+		t.Error("chapters written to an ID3v2.2 tag")
+	}
+}
+
+func TestFormatsRoundTrip(t *testing.T) {
+	chapters := []Chapter{
+		{Start: 0, End: 90 * time.Second, Title: "Intro"},
+		{Start: 90 * time.Second, End: 5 * time.Minute, Title: `News; "and" #more`},
+	}
+	var buf bytes.Buffer
+
+	if err := WriteJSON(&buf, chapters); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadJSON(&buf); err != nil || !reflect.DeepEqual(got, chapters) {
+		t.Errorf("JSON: got %+v, %v", got, err)
+	}
+
+	buf.Reset()
+	if err := WriteFFMetadata(&buf, chapters); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadFFMetadata(&buf); err != nil || !reflect.DeepEqual(got, chapters) {
+		t.Errorf("FFmpeg metadata: got %+v, %v", got, err)
+	}
+
+	// Podlove and CUE sheets only hold start times.
+	starts := []Chapter{
+		{Start: 0, Title: "Intro"},
+		{Start: 90*time.Second + 480*time.Millisecond, Title: "News & more"},
+	}
+	buf.Reset()
+	if err := WritePodlove(&buf, starts); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadPodlove(&buf); err != nil || !reflect.DeepEqual(got, starts) {
+		t.Errorf("Podlove: got %+v, %v", got, err)
+	}
+
+	buf.Reset()
+	if err := WriteCUE(&buf, "episode.mp3", starts); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadCUE(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range got {
+		got[i].ID = ""
+	}
+	if !reflect.DeepEqual(got, starts) {
+		t.Errorf("CUE: got %+v", got)
+	}
+}
+
+func TestReadCUE(t *testing.T) {
+	cue := `FILE "episode.mp3" MP3
+  TRACK 01 AUDIO
+    TITLE "Intro"
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "Part two"
+    INDEX 00 01:29:00
+    INDEX 01 01:30:36
+FILE "other.mp3" MP3
+  TRACK 03 AUDIO
+    INDEX 01 00:00:00
+`
+	got, err := ReadCUE(strings.NewReader(cue))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Chapter{
+		{ID: "chp01", Title: "Intro"},
+		{ID: "chp02", Title: "Part two", Start: 90*time.Second + 480*time.Millisecond},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
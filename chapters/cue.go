@@ -0,0 +1,138 @@
+package chapters
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cueFramesPerSecond is the number of CD frames per second that CUE sheet
+// times are counted in.
+const cueFramesPerSecond = 75
+
+// ReadCUE reads the tracks of a CUE sheet as chapters, starting at their
+// INDEX 01 and titled by their TITLE. Only the first FILE is considered as
+// chapters apply to a single audio file.
+func ReadCUE(r io.Reader) ([]Chapter, error) {
+	var chapters []Chapter
+	var current *Chapter
+	files := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := cueFields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "FILE":
+			files++
+		case "TRACK":
+			if files > 1 {
+				return chapters, nil
+			}
+			chapters = append(chapters, Chapter{})
+			current = &chapters[len(chapters)-1]
+			if len(fields) > 1 {
+				current.ID = "chp" + fields[1]
+			}
+		case "TITLE":
+			if current != nil && len(fields) > 1 {
+				current.Title = fields[1]
+			}
+		case "INDEX":
+			if current == nil || len(fields) < 3 || fields[1] != "01" {
+				continue
+			}
+			t, err := parseCUETime(fields[2])
+			if err != nil {
+				return nil, err
+			}
+			current.Start = t
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return chapters, nil
+}
+
+// WriteCUE writes chapters as the tracks of a CUE sheet referring to the
+// given MP3 file. Times are rounded to the nearest CD frame.
+func WriteCUE(w io.Writer, filename string, chapters []Chapter) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "FILE %s MP3\n", quoteCUE(filename))
+	for i, c := range chapters {
+		fmt.Fprintf(bw, "  TRACK %02d AUDIO\n", i+1)
+		if c.Title != "" {
+			fmt.Fprintf(bw, "    TITLE %s\n", quoteCUE(c.Title))
+		}
+		fmt.Fprintf(bw, "    INDEX 01 %s\n", formatCUETime(c.Start))
+	}
+	return bw.Flush()
+}
+
+// cueFields splits a CUE sheet line into fields, keeping quoted strings
+// together.
+func cueFields(line string) []string {
+	var fields []string
+	line = strings.TrimSpace(line)
+	for line != "" {
+		if line[0] == '"' {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				fields = append(fields, line[1:])
+				break
+			}
+			fields = append(fields, line[1:end+1])
+			line = strings.TrimSpace(line[end+2:])
+			continue
+		}
+		end := strings.IndexAny(line, " \t")
+		if end < 0 {
+			fields = append(fields, line)
+			break
+		}
+		fields = append(fields, line[:end])
+		line = strings.TrimSpace(line[end:])
+	}
+	return fields
+}
+
+// cueQuoteReplacer replaces what cannot be written within a quoted CUE
+// sheet string, which has no escape sequences.
+var cueQuoteReplacer = strings.NewReplacer(`"`, "'", "\r\n", " ", "\n", " ")
+
+// quoteCUE quotes a string for a CUE sheet.
+func quoteCUE(s string) string {
+	return `"` + cueQuoteReplacer.Replace(s) + `"`
+}
+
+// parseCUETime parses a time given as mm:ss:ff, rounded to the millisecond
+// resolution of CHAP frames.
+func parseCUETime(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid CUE time: %s", s)
+	}
+	var n [3]int64
+	for i, part := range parts {
+		v, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("invalid CUE time: %s", s)
+		}
+		n[i] = v
+	}
+	frames := (n[0]*60+n[1])*cueFramesPerSecond + n[2]
+	d := time.Duration(frames) * time.Second / cueFramesPerSecond
+	return d.Round(time.Millisecond), nil
+}
+
+// formatCUETime formats a time as mm:ss:ff.
+func formatCUETime(d time.Duration) string {
+	frames := int64((d*cueFramesPerSecond + time.Second/2) / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", frames/cueFramesPerSecond/60,
+		frames/cueFramesPerSecond%60, frames%cueFramesPerSecond)
+}
@@ -0,0 +1,172 @@
+package chapters
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ffmetadataHeader starts every FFmpeg metadata file.
+const ffmetadataHeader = ";FFMETADATA1"
+
+// ReadFFMetadata reads the chapters of an FFmpeg metadata file, as written
+// by "ffmpeg -i input -f ffmetadata". Global metadata and stream sections
+// are ignored.
+//
+// Refer to https://ffmpeg.org/ffmpeg-formats.html#Metadata-2
+func ReadFFMetadata(r io.Reader) ([]Chapter, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), ffmetadataHeader) {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("missing %s header", ffmetadataHeader)
+	}
+
+	var chapters []Chapter
+	var current *ffChapter
+	flush := func() {
+		if current != nil {
+			chapters = append(chapters, Chapter{
+				Start: current.duration(current.start),
+				End:   current.duration(current.end),
+				Title: current.title,
+			})
+			current = nil
+		}
+	}
+	var line string
+	for scanner.Scan() {
+		// A backslash at the end of a line escapes the newline.
+		text := scanner.Text()
+		if strings.HasSuffix(text, "\\") && !strings.HasSuffix(text, "\\\\") {
+			line += text[:len(text)-1] + "\n"
+			continue
+		}
+		line += text
+		text, line = line, ""
+
+		if text == "" || text[0] == ';' || text[0] == '#' {
+			continue
+		}
+		if text[0] == '[' {
+			flush()
+			if strings.TrimSpace(text) == "[CHAPTER]" {
+				current = &ffChapter{timebase: big.NewRat(1, 1000000000)}
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		key, value, ok := splitFFMetadata(text)
+		if !ok {
+			return nil, fmt.Errorf("invalid ffmetadata line: %s", text)
+		}
+		if err := current.set(strings.ToLower(key), value); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return chapters, nil
+}
+
+type ffChapter struct {
+	timebase   *big.Rat
+	start, end int64
+	title      string
+}
+
+func (c *ffChapter) set(key, value string) error {
+	var err error
+	switch key {
+	case "timebase":
+		if _, ok := c.timebase.SetString(value); !ok || c.timebase.Sign() <= 0 {
+			return fmt.Errorf("invalid chapter timebase: %s", value)
+		}
+	case "start":
+		if c.start, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("invalid chapter start: %s", value)
+		}
+	case "end":
+		if c.end, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("invalid chapter end: %s", value)
+		}
+	case "title":
+		c.title = value
+	}
+	return nil
+}
+
+// duration converts a time in timebase units to a duration.
+func (c *ffChapter) duration(t int64) time.Duration {
+	d := new(big.Rat).Mul(big.NewRat(t, 1), c.timebase)
+	d.Mul(d, big.NewRat(int64(time.Second), 1))
+	n := new(big.Int).Quo(d.Num(), d.Denom())
+	return time.Duration(n.Int64())
+}
+
+// splitFFMetadata splits a line at the first unescaped "=" and unescapes
+// the key and value.
+func splitFFMetadata(line string) (string, string, bool) {
+	var key strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if i+1 < len(line) {
+				i++
+				key.WriteByte(line[i])
+			}
+		case '=':
+			return key.String(), unescapeFFMetadata(line[i+1:]), true
+		default:
+			key.WriteByte(line[i])
+		}
+	}
+	return "", "", false
+}
+
+func unescapeFFMetadata(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+var ffmetadataEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`=`, `\=`,
+	`;`, `\;`,
+	`#`, `\#`,
+	"\n", "\\\n",
+)
+
+// WriteFFMetadata writes chapters in the FFmpeg metadata format with a
+// millisecond timebase, suitable for "ffmpeg -i input -i chapters
+// -map_metadata 1". Chapters without an end time end where the next one
+// starts.
+func WriteFFMetadata(w io.Writer, chapters []Chapter) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, ffmetadataHeader)
+	for _, c := range withEndTimes(chapters, 0) {
+		fmt.Fprintln(bw, "[CHAPTER]")
+		fmt.Fprintln(bw, "TIMEBASE=1/1000")
+		fmt.Fprintf(bw, "START=%d\n", c.Start/time.Millisecond)
+		fmt.Fprintf(bw, "END=%d\n", c.End/time.Millisecond)
+		if c.Title != "" {
+			fmt.Fprintf(bw, "title=%s\n", ffmetadataEscaper.Replace(c.Title))
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,83 @@
+package chapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// jsonVersion is the version of the Podcasting 2.0 chapters format written.
+const jsonVersion = "1.2.0"
+
+type jsonChapters struct {
+	Version  string        `json:"version"`
+	Chapters []jsonChapter `json:"chapters"`
+}
+
+type jsonChapter struct {
+	StartTime float64  `json:"startTime"`
+	EndTime   *float64 `json:"endTime,omitempty"`
+	Title     string   `json:"title,omitempty"`
+	Img       string   `json:"img,omitempty"`
+	URL       string   `json:"url,omitempty"`
+	TOC       *bool    `json:"toc,omitempty"`
+}
+
+// ReadJSON reads chapters in the Podcasting 2.0 JSON chapters format.
+// Chapters with "toc" set to false are only meant for display while playing
+// and are skipped.
+//
+// Refer to https://github.com/Podcastindex-org/podcast-namespace/blob/main/docs/examples/chapters/jsonChapters.md
+func ReadJSON(r io.Reader) ([]Chapter, error) {
+	var doc jsonChapters
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON chapters: %s", err)
+	}
+	var chapters []Chapter
+	for _, c := range doc.Chapters {
+		if c.TOC != nil && !*c.TOC {
+			continue
+		}
+		chapter := Chapter{
+			Start:    seconds(c.StartTime),
+			Title:    c.Title,
+			URL:      c.URL,
+			ImageURL: c.Img,
+		}
+		if c.EndTime != nil {
+			chapter.End = seconds(*c.EndTime)
+		}
+		chapters = append(chapters, chapter)
+	}
+	return chapters, nil
+}
+
+// WriteJSON writes chapters in the Podcasting 2.0 JSON chapters format.
+// Embedded images cannot be represented and are left out.
+func WriteJSON(w io.Writer, chapters []Chapter) error {
+	doc := jsonChapters{Version: jsonVersion, Chapters: []jsonChapter{}}
+	for _, c := range chapters {
+		chapter := jsonChapter{
+			StartTime: c.Start.Seconds(),
+			Title:     c.Title,
+			Img:       c.ImageURL,
+			URL:       c.URL,
+		}
+		if c.End != 0 {
+			end := c.End.Seconds()
+			chapter.EndTime = &end
+		}
+		doc.Chapters = append(doc.Chapters, chapter)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// seconds converts fractional seconds to a duration rounded to the
+// millisecond, the resolution of CHAP frames.
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Round(s*1000)) * time.Millisecond
+}
@@ -0,0 +1,123 @@
+package chapters
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// podloveNamespace is the XML namespace of Podlove Simple Chapters.
+const podloveNamespace = "http://podlove.org/simple-chapters"
+
+type podloveChapters struct {
+	XMLName  xml.Name         `xml:"http://podlove.org/simple-chapters chapters"`
+	Version  string           `xml:"version,attr"`
+	Chapters []podloveChapter `xml:"chapter"`
+}
+
+type podloveChapter struct {
+	Start string `xml:"start,attr"`
+	Title string `xml:"title,attr"`
+	Href  string `xml:"href,attr,omitempty"`
+	Image string `xml:"image,attr,omitempty"`
+}
+
+// ReadPodlove reads chapters in the Podlove Simple Chapters format. The
+// psc:chapters element may be a document of its own or embedded in a feed,
+// in which case the first one found is used.
+//
+// Refer to https://podlove.org/simple-chapters/
+func ReadPodlove(r io.Reader) ([]Chapter, error) {
+	dec := xml.NewDecoder(r)
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no Podlove chapters found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Space != podloveNamespace || start.Name.Local != "chapters" {
+			continue
+		}
+		var doc podloveChapters
+		if err := dec.DecodeElement(&doc, &start); err != nil {
+			return nil, err
+		}
+		var chapters []Chapter
+		for _, c := range doc.Chapters {
+			t, err := parseNormalPlayTime(c.Start)
+			if err != nil {
+				return nil, err
+			}
+			chapters = append(chapters, Chapter{
+				Start:    t,
+				Title:    c.Title,
+				URL:      c.Href,
+				ImageURL: c.Image,
+			})
+		}
+		return chapters, nil
+	}
+}
+
+// WritePodlove writes chapters as a standalone Podlove Simple Chapters
+// document. The format has no end times and no embedded images.
+func WritePodlove(w io.Writer, chapters []Chapter) error {
+	doc := podloveChapters{Version: "1.2"}
+	for _, c := range chapters {
+		doc.Chapters = append(doc.Chapters, podloveChapter{
+			Start: formatNormalPlayTime(c.Start),
+			Title: c.Title,
+			Href:  c.URL,
+			Image: c.ImageURL,
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// parseNormalPlayTime parses a time such as "1:02:03.500", "02:03" or
+// "123.5", following the Normal Play Time of RFC 2326 used by Podlove.
+func parseNormalPlayTime(s string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid chapter time: %s", s)
+	}
+	var d time.Duration
+	for i, part := range parts {
+		last := i == len(parts)-1
+		if last {
+			seconds, err := strconv.ParseFloat(part, 64)
+			if err != nil || seconds < 0 {
+				return 0, fmt.Errorf("invalid chapter time: %s", s)
+			}
+			d = d*60 + time.Duration(seconds*float64(time.Second)+0.5)
+			break
+		}
+		n, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid chapter time: %s", s)
+		}
+		d = d*60 + time.Duration(n)*time.Second
+	}
+	return d.Round(time.Millisecond), nil
+}
+
+// formatNormalPlayTime formats a time as HH:MM:SS.mmm.
+func formatNormalPlayTime(d time.Duration) string {
+	ms := int64(d / time.Millisecond)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
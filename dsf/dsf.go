@@ -0,0 +1,159 @@
+// Package dsf reads and writes the ID3v2 tag of DSD Stream Files, which is
+// stored at the end of the file at the offset given by the DSD chunk, and
+// reports the audio properties of their fmt chunk.
+//
+// Refer to the DSF File Format Specification by Sony.
+package dsf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+const (
+	dsdChunkSize = 28
+	fmtChunkSize = 52
+)
+
+// Properties are the audio properties given by the fmt chunk. ChannelType
+// is 1 for mono, 2 for stereo, 3 for three channels, 4 for quad, 5 for four
+// channels, 6 for five channels and 7 for 5.1.
+type Properties struct {
+	Channels      int
+	ChannelType   int
+	SampleRate    int
+	BitsPerSample int
+	Samples       uint64
+	Duration      time.Duration
+}
+
+// header holds the DSD chunk and the end of the data chunk, where the
+// metadata starts.
+type header struct {
+	fileSize        uint64
+	metadataPointer uint64
+	dataEnd         int64
+	fmt             []byte
+}
+
+func readHeader(r io.ReadSeeker) (*header, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data := make([]byte, dsdChunkSize+fmtChunkSize+12)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("invalid DSF header: %s", err)
+	}
+	if string(data[0:4]) != "DSD " || binary.LittleEndian.Uint64(data[4:12]) != dsdChunkSize {
+		return nil, fmt.Errorf("invalid DSF header")
+	}
+	f := data[dsdChunkSize : dsdChunkSize+fmtChunkSize]
+	if string(f[0:4]) != "fmt " || binary.LittleEndian.Uint64(f[4:12]) != fmtChunkSize {
+		return nil, fmt.Errorf("invalid DSF fmt chunk")
+	}
+	d := data[dsdChunkSize+fmtChunkSize:]
+	if size := binary.LittleEndian.Uint64(d[4:12]); string(d[0:4]) != "data" || size < 12 || size > 1<<62 {
+		return nil, fmt.Errorf("invalid DSF data chunk")
+	}
+	h := &header{
+		fileSize:        binary.LittleEndian.Uint64(data[12:20]),
+		metadataPointer: binary.LittleEndian.Uint64(data[20:28]),
+		dataEnd:         dsdChunkSize + fmtChunkSize + int64(binary.LittleEndian.Uint64(d[4:12])),
+		fmt:             f,
+	}
+	// The tag cannot start before the audio data.
+	if h.metadataPointer != 0 && h.metadataPointer < dsdChunkSize+fmtChunkSize+12 {
+		return nil, fmt.Errorf("invalid DSF metadata pointer %d", h.metadataPointer)
+	}
+	return h, nil
+}
+
+// Read returns the ID3v2 tag of a DSF file, or nil when it has none.
+func Read(r io.ReadSeeker) (*v2.ID3v2Tag, error) {
+	h, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if h.metadataPointer == 0 {
+		return nil, nil
+	}
+	if _, err := r.Seek(int64(h.metadataPointer), io.SeekStart); err != nil {
+		return nil, err
+	}
+	return v2.Read(r)
+}
+
+// Write copies the DSF file from r to w with its ID3v2 tag replaced by tag,
+// or removed when tag is nil. The tag is written right after the data chunk
+// and the metadata pointer and file size of the DSD chunk are updated.
+func Write(w io.Writer, r io.ReadSeeker, tag *v2.ID3v2Tag) error {
+	h, err := readHeader(r)
+	if err != nil {
+		return err
+	}
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	// The audio ends at the data chunk, or at the old tag when the data
+	// chunk size is wrong.
+	audioEnd := h.dataEnd
+	if h.metadataPointer > 0 && int64(h.metadataPointer) < audioEnd {
+		audioEnd = int64(h.metadataPointer)
+	}
+	if audioEnd > end {
+		audioEnd = end
+	}
+
+	var data []byte
+	if tag != nil {
+		if data, err = tag.Bytes(); err != nil {
+			return err
+		}
+	}
+	dsd := make([]byte, dsdChunkSize)
+	copy(dsd, "DSD ")
+	binary.LittleEndian.PutUint64(dsd[4:12], dsdChunkSize)
+	binary.LittleEndian.PutUint64(dsd[12:20], uint64(audioEnd)+uint64(len(data)))
+	if data != nil {
+		binary.LittleEndian.PutUint64(dsd[20:28], uint64(audioEnd))
+	}
+	if _, err := w.Write(dsd); err != nil {
+		return err
+	}
+	if _, err := r.Seek(dsdChunkSize, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(w, r, audioEnd-dsdChunkSize); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// ReadProperties returns the audio properties of a DSF file.
+func ReadProperties(r io.ReadSeeker) (*Properties, error) {
+	h, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	f := h.fmt
+	if id := binary.LittleEndian.Uint32(f[16:20]); id != 0 {
+		return nil, fmt.Errorf("unsupported DSF format ID %d", id)
+	}
+	p := &Properties{
+		ChannelType:   int(binary.LittleEndian.Uint32(f[20:24])),
+		Channels:      int(binary.LittleEndian.Uint32(f[24:28])),
+		SampleRate:    int(binary.LittleEndian.Uint32(f[28:32])),
+		BitsPerSample: int(binary.LittleEndian.Uint32(f[32:36])),
+		Samples:       binary.LittleEndian.Uint64(f[36:44]),
+	}
+	if p.SampleRate > 0 {
+		p.Duration = time.Duration(float64(p.Samples) / float64(p.SampleRate) * float64(time.Second))
+	}
+	return p, nil
+}
@@ -0,0 +1,103 @@
+package dsf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+// testDSF returns a stereo DSD64 file of one second, with the given tag
+// appended and pointed to.
+func testDSF(tag []byte) []byte {
+	audio := make([]byte, 4096)
+	file := make([]byte, dsdChunkSize+fmtChunkSize+12)
+	copy(file, "DSD ")
+	binary.LittleEndian.PutUint64(file[4:], dsdChunkSize)
+	f := file[dsdChunkSize:]
+	copy(f, "fmt ")
+	binary.LittleEndian.PutUint64(f[4:], fmtChunkSize)
+	binary.LittleEndian.PutUint32(f[12:], 1)
+	binary.LittleEndian.PutUint32(f[20:], 2)
+	binary.LittleEndian.PutUint32(f[24:], 2)
+	binary.LittleEndian.PutUint32(f[28:], 2822400)
+	binary.LittleEndian.PutUint32(f[32:], 1)
+	binary.LittleEndian.PutUint64(f[36:], 2822400)
+	binary.LittleEndian.PutUint32(f[44:], 4096)
+	d := f[fmtChunkSize:]
+	copy(d, "data")
+	binary.LittleEndian.PutUint64(d[4:], uint64(12+len(audio)))
+	file = append(file, audio...)
+	if tag != nil {
+		binary.LittleEndian.PutUint64(file[20:], uint64(len(file)))
+	}
+	file = append(file, tag...)
+	binary.LittleEndian.PutUint64(file[12:], uint64(len(file)))
+	return file
+}
+
+func TestWriteRead(t *testing.T) {
+	file := testDSF(nil)
+	if tag, err := Read(bytes.NewReader(file)); err != nil || tag != nil {
+		t.Fatalf("untagged file: %v, %v", tag, err)
+	}
+
+	for _, title := range []string{"A much longer first title", "Short"} {
+		tag := v2.NewID3v2Tag(3)
+		tag.SetTextValues("TIT2", title)
+		var buf bytes.Buffer
+		if err := Write(&buf, bytes.NewReader(file), tag); err != nil {
+			t.Fatal(err)
+		}
+		data, err := tag.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := testDSF(data); !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("%q: pointer %d, size %d, want %d and %d", title,
+				binary.LittleEndian.Uint64(buf.Bytes()[20:]), binary.LittleEndian.Uint64(buf.Bytes()[12:]),
+				binary.LittleEndian.Uint64(want[20:]), binary.LittleEndian.Uint64(want[12:]))
+		}
+		file = buf.Bytes()
+		read, err := Read(bytes.NewReader(file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read == nil || read.Get("title") != title {
+			t.Errorf("read %v", read)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, bytes.NewReader(file), nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), testDSF(nil)) {
+		t.Error("removing the tag did not restore the untagged file")
+	}
+}
+
+func TestInvalidMetadataPointer(t *testing.T) {
+	file := testDSF(nil)
+	binary.LittleEndian.PutUint64(file[20:], 40)
+	var buf bytes.Buffer
+	if err := Write(&buf, bytes.NewReader(file), v2.NewID3v2Tag(3)); err == nil {
+		t.Error("metadata pointer inside the header accepted")
+	}
+	if _, err := Read(bytes.NewReader(file)); err == nil {
+		t.Error("metadata pointer inside the header read")
+	}
+}
+
+func TestReadProperties(t *testing.T) {
+	p, err := ReadProperties(bytes.NewReader(testDSF(nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Properties{Channels: 2, ChannelType: 2, SampleRate: 2822400, BitsPerSample: 1, Samples: 2822400, Duration: time.Second}
+	if *p != want {
+		t.Errorf("got %+v, want %+v", *p, want)
+	}
+}
@@ -0,0 +1,224 @@
+// Package iff walks and rewrites the chunks of files following the
+// Interchange File Format: RIFF (little endian) and AIFF (big endian).
+package iff
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Chunk is a chunk of a container. Offset is the position of its 8 byte
+// header in the file and Size the length of its data, which is followed by
+// a pad byte when odd.
+type Chunk struct {
+	ID     string
+	Offset int64
+	Size   uint32
+}
+
+// dataOffset returns the position of the chunk data.
+func (c Chunk) dataOffset() int64 {
+	return c.Offset + 8
+}
+
+// paddedSize returns the length of the chunk data including its pad byte.
+func paddedSize(size uint32) int64 {
+	return int64(size) + int64(size&1)
+}
+
+// Container is the outer chunk of a file, such as "RIFF" with the form type
+// "WAVE" or "FORM" with "AIFF", and the chunks it holds.
+type Container struct {
+	Order  binary.ByteOrder
+	ID     string
+	Form   string
+	Chunks []Chunk
+
+	// trailing and fileEnd delimit the data following the container, such
+	// as an ID3v1 tag, which is kept as is when rewriting it.
+	trailing int64
+	fileEnd  int64
+}
+
+// Read walks the chunks of a container with the given outer chunk ID. The
+// chunks are read up to the end of the container when its size fits the
+// file, the data after it being kept apart. A container size that does not
+// fit is tolerated, the chunks being read up to the end of the file. A
+// truncated last chunk is shortened to the data present.
+func Read(r io.ReadSeeker, order binary.ByteOrder, id string) (*Container, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("invalid %s header: %s", id, err)
+	}
+	if string(header[:4]) != id {
+		return nil, fmt.Errorf("invalid %s header", id)
+	}
+	c := &Container{Order: order, ID: id, Form: string(header[8:12]), fileEnd: end}
+	limit := end
+	if size := int64(order.Uint32(header[4:8])); size >= 4 && 8+size <= end {
+		limit = 8 + size
+	}
+	offset := int64(12)
+	for offset+8 <= limit {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return nil, err
+		}
+		chunk := Chunk{ID: string(header[:4]), Offset: offset, Size: order.Uint32(header[4:8])}
+		if chunk.dataOffset()+int64(chunk.Size) > limit {
+			chunk.Size = uint32(limit - chunk.dataOffset())
+		}
+		c.Chunks = append(c.Chunks, chunk)
+		offset += 8 + paddedSize(chunk.Size)
+	}
+	// The pad byte of the last chunk may be left out of the container size.
+	c.trailing = limit
+	if offset > limit {
+		c.trailing = min(offset, end)
+	}
+	return c, nil
+}
+
+// Find returns the first chunk with one of the given IDs.
+func (c *Container) Find(ids ...string) (Chunk, bool) {
+	for _, chunk := range c.Chunks {
+		for _, id := range ids {
+			if chunk.ID == id {
+				return chunk, true
+			}
+		}
+	}
+	return Chunk{}, false
+}
+
+// ReadChunk returns the data of a chunk.
+func (c *Container) ReadChunk(r io.ReadSeeker, chunk Chunk) ([]byte, error) {
+	if _, err := r.Seek(chunk.dataOffset(), io.SeekStart); err != nil {
+		return nil, err
+	}
+	data := make([]byte, chunk.Size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("truncated %q chunk: %s", chunk.ID, err)
+	}
+	return data, nil
+}
+
+// Replace copies the container from r to w with the first chunk having one
+// of the given IDs replaced by data, keeping its ID, and the others
+// removed. When there is none, a chunk with the first ID is appended. A nil
+// data removes the chunks. The container size is updated and the data
+// following the container copied unchanged.
+func (c *Container) Replace(w io.Writer, r io.ReadSeeker, ids []string, data []byte) error {
+	matches := func(chunk Chunk) bool {
+		for _, id := range ids {
+			if chunk.ID == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	replaced := false
+	size := int64(4)
+	for _, chunk := range c.Chunks {
+		if !matches(chunk) {
+			size += 8 + paddedSize(chunk.Size)
+		} else if data != nil && !replaced {
+			size += 8 + paddedSize(uint32(len(data)))
+			replaced = true
+		}
+	}
+	if data != nil && !replaced {
+		size += 8 + paddedSize(uint32(len(data)))
+	}
+	if size > 0xFFFFFFFF {
+		return fmt.Errorf("%s container too large: %d bytes", c.ID, size)
+	}
+
+	header := make([]byte, 12)
+	copy(header, c.ID)
+	c.Order.PutUint32(header[4:8], uint32(size))
+	copy(header[8:], c.Form)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	written := false
+	for _, chunk := range c.Chunks {
+		var err error
+		switch {
+		case !matches(chunk):
+			err = c.copyChunk(w, r, chunk)
+		case data != nil && !written:
+			err = c.writeChunk(w, chunk.ID, data)
+			written = true
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if data != nil && !written {
+		if err := c.writeChunk(w, ids[0], data); err != nil {
+			return err
+		}
+	}
+	if c.trailing >= c.fileEnd {
+		return nil
+	}
+	if _, err := r.Seek(c.trailing, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := io.CopyN(w, r, c.fileEnd-c.trailing)
+	return err
+}
+
+func (c *Container) copyChunk(w io.Writer, r io.ReadSeeker, chunk Chunk) error {
+	if err := c.writeHeader(w, chunk.ID, chunk.Size); err != nil {
+		return err
+	}
+	if _, err := r.Seek(chunk.dataOffset(), io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(w, r, int64(chunk.Size)); err != nil {
+		return err
+	}
+	// The pad byte is written rather than copied as it may be missing at the
+	// end of the file.
+	return writePad(w, chunk.Size)
+}
+
+func (c *Container) writeChunk(w io.Writer, id string, data []byte) error {
+	if err := c.writeHeader(w, id, uint32(len(data))); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return writePad(w, uint32(len(data)))
+}
+
+func (c *Container) writeHeader(w io.Writer, id string, size uint32) error {
+	header := make([]byte, 8)
+	copy(header, id)
+	c.Order.PutUint32(header[4:], size)
+	_, err := w.Write(header)
+	return err
+}
+
+// writePad writes the pad byte aligning odd sized chunks to a word.
+func writePad(w io.Writer, size uint32) error {
+	if size%2 == 0 {
+		return nil
+	}
+	_, err := w.Write([]byte{0})
+	return err
+}
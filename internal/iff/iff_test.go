@@ -0,0 +1,111 @@
+package iff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// testChunk returns a little endian chunk, padded when odd.
+func testChunk(id string, data []byte) []byte {
+	chunk := append([]byte(id), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(len(data)))
+	chunk = append(chunk, data...)
+	if len(data)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+// testRIFF returns a RIFF container holding chunks, with the given size
+// added to the one of its content.
+func testRIFF(delta int, chunks ...[]byte) []byte {
+	body := bytes.Join(chunks, nil)
+	file := []byte("RIFF\x00\x00\x00\x00WAVE")
+	binary.LittleEndian.PutUint32(file[4:], uint32(4+len(body)+delta))
+	return append(file, body...)
+}
+
+func chunkIDs(c *Container) []string {
+	var ids []string
+	for _, chunk := range c.Chunks {
+		ids = append(ids, chunk.ID)
+	}
+	return ids
+}
+
+func TestReplace(t *testing.T) {
+	fmtChunk := testChunk("fmt ", make([]byte, 16))
+	odd := testChunk("LIST", []byte("odd"))
+	data := testChunk("data", []byte{1, 2, 3, 4, 5})
+	id3v1 := append([]byte("TAG"), make([]byte, 125)...)
+	file := append(testRIFF(0, fmtChunk, odd, data), id3v1...)
+
+	c, err := Read(bytes.NewReader(file), binary.LittleEndian, "RIFF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Chunk{{"fmt ", 12, 16}, {"LIST", 36, 3}, {"data", 48, 5}}
+	if c.Form != "WAVE" || !reflect.DeepEqual(c.Chunks, want) {
+		t.Fatalf("read %s %+v", c.Form, c.Chunks)
+	}
+	if got, err := c.ReadChunk(bytes.NewReader(file), c.Chunks[1]); err != nil || string(got) != "odd" {
+		t.Errorf("LIST chunk = %q, %v", got, err)
+	}
+
+	tag := []byte("ID3 tag")
+	tests := []struct {
+		name string
+		file []byte
+		ids  []string
+		data []byte
+		want []byte
+	}{
+		{"append", file, []string{"id3 "}, tag,
+			append(testRIFF(0, fmtChunk, odd, data, testChunk("id3 ", tag)), id3v1...)},
+		{"replace", file, []string{"data"}, []byte("new"),
+			append(testRIFF(0, fmtChunk, odd, testChunk("data", []byte("new"))), id3v1...)},
+		{"remove", file, []string{"LIST"}, nil,
+			append(testRIFF(0, fmtChunk, data), id3v1...)},
+		// The pad byte of the last chunk is left out of the container size.
+		{"unpadded", append(testRIFF(-1, fmtChunk, odd), id3v1...), []string{"id3 "}, tag,
+			append(testRIFF(0, fmtChunk, odd, testChunk("id3 ", tag)), id3v1...)},
+		// A size beyond the file is ignored.
+		{"oversized", testRIFF(100, fmtChunk, odd), []string{"id3 "}, tag,
+			testRIFF(0, fmtChunk, odd, testChunk("id3 ", tag))},
+	}
+	for _, tt := range tests {
+		r := bytes.NewReader(tt.file)
+		c, err := Read(r, binary.LittleEndian, "RIFF")
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		var buf bytes.Buffer
+		if err := c.Replace(&buf, r, tt.ids, tt.data); err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if !bytes.Equal(buf.Bytes(), tt.want) {
+			t.Errorf("%s: got\n%q\nwant\n%q", tt.name, buf.Bytes(), tt.want)
+		}
+	}
+}
+
+func TestReadTruncated(t *testing.T) {
+	file := testRIFF(0, testChunk("fmt ", make([]byte, 16)), testChunk("data", make([]byte, 100)))
+	file = file[:len(file)-60]
+	c, err := Read(bytes.NewReader(file), binary.LittleEndian, "RIFF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := chunkIDs(c); !reflect.DeepEqual(got, []string{"fmt ", "data"}) || c.Chunks[1].Size != 40 {
+		t.Errorf("read %+v", c.Chunks)
+	}
+
+	if _, err := Read(bytes.NewReader([]byte("FORM\x00\x00\x00\x04AIFF")), binary.LittleEndian, "RIFF"); err == nil {
+		t.Error("FORM read as RIFF")
+	}
+	if _, err := Read(bytes.NewReader([]byte("RIFF")), binary.LittleEndian, "RIFF"); err == nil {
+		t.Error("short header read")
+	}
+}
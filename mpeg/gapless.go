@@ -0,0 +1,95 @@
+package mpeg
+
+import (
+	"strconv"
+	"strings"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+// GaplessInfo tells how many samples to drop from the start and the end of
+// the decoded stream to recover the original audio.
+type GaplessInfo struct {
+	EncoderDelay int
+	Padding      int
+	ValidSamples int64
+}
+
+// decoderDelay is the delay of the MPEG Layer III decoder, which LAME does
+// not count in its encoder delay and padding, unlike iTunes.
+const decoderDelay = 528 + 1
+
+// iTunesGaplessDescription is the description of the comment in which
+// iTunes stores the gapless information.
+const iTunesGaplessDescription = "iTunSMPB"
+
+// Gapless returns the gapless information of a stream, taken from the
+// iTunes iTunSMPB comment of the tag, which may be nil, or from the LAME
+// header. Both give the counts the stream was encoded with, so the one
+// matching the measured frame count is preferred, the LAME header if both
+// do.
+func Gapless(tag *v2.ID3v2Tag, info *Info) (GaplessInfo, bool) {
+	total := info.Samples
+	lame, hasLAME := lameGapless(info)
+	itunes, hasITunes := iTunesGapless(tag)
+	switch {
+	case hasLAME && info.Xing.Frames == uint32(info.Frames):
+		return lame, true
+	case hasITunes && int64(itunes.EncoderDelay+itunes.Padding)+itunes.ValidSamples == total:
+		return itunes, true
+	case hasLAME:
+		return lame, true
+	}
+	return itunes, hasITunes
+}
+
+func lameGapless(info *Info) (GaplessInfo, bool) {
+	if info.LAME == nil || info.Xing == nil {
+		return GaplessInfo{}, false
+	}
+	g := GaplessInfo{
+		EncoderDelay: info.LAME.EncoderDelay + decoderDelay,
+		Padding:      info.LAME.Padding - decoderDelay,
+	}
+	if g.Padding < 0 {
+		g.Padding = 0
+	}
+	g.ValidSamples = info.Samples - int64(g.EncoderDelay+g.Padding)
+	if g.ValidSamples < 0 {
+		return GaplessInfo{}, false
+	}
+	return g, true
+}
+
+// iTunesGapless reads the iTunSMPB comment, hexadecimal values of which the
+// second to fourth are the encoder delay, the padding and the number of
+// valid samples.
+func iTunesGapless(tag *v2.ID3v2Tag) (GaplessInfo, bool) {
+	if tag == nil {
+		return GaplessInfo{}, false
+	}
+	for _, frame := range tag.Frames {
+		u, ok := frame.Data.(*v2.UnsynchTextFrame)
+		if !ok || u.Description != iTunesGaplessDescription {
+			continue
+		}
+		fields := strings.Fields(u.Text)
+		if len(fields) < 4 {
+			return GaplessInfo{}, false
+		}
+		var values [3]int64
+		for i := range values {
+			v, err := strconv.ParseInt(fields[i+1], 16, 64)
+			if err != nil {
+				return GaplessInfo{}, false
+			}
+			values[i] = v
+		}
+		return GaplessInfo{
+			EncoderDelay: int(values[0]),
+			Padding:      int(values[1]),
+			ValidSamples: values[2],
+		}, true
+	}
+	return GaplessInfo{}, false
+}
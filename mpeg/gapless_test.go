@@ -0,0 +1,54 @@
+package mpeg
+
+import (
+	"bytes"
+	"testing"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+func TestGapless(t *testing.T) {
+	analyze := func(xingFrames uint32) *Info {
+		var file bytes.Buffer
+		file.Write(xingFrame(false, xingFrames, 0, true, 576, 1260))
+		for i := 0; i < 4; i++ {
+			file.Write(testFrame(header128, 1))
+		}
+		info, err := Analyze(bytes.NewReader(file.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info
+	}
+	// iTunes counts the decoder delay, LAME does not.
+	lame := GaplessInfo{EncoderDelay: 576 + 529, Padding: 1260 - 529, ValidSamples: 4*1152 - 576 - 1260}
+	itunes := GaplessInfo{EncoderDelay: 0x840, Padding: 0x1CA, ValidSamples: 4*1152 - 0x840 - 0x1CA}
+	tag := v2.NewID3v2Tag(3)
+	tag.Frames = append(tag.Frames, &v2.ID3v2Frame{Id: "COMM", Data: &v2.UnsynchTextFrame{
+		Language:    "eng",
+		Description: iTunesGaplessDescription,
+		Text:        " 00000000 00000840 000001CA 00000000000007F6 00000000 00000000",
+	}})
+
+	tests := []struct {
+		tag        *v2.ID3v2Tag
+		xingFrames uint32
+		want       GaplessInfo
+	}{
+		{nil, 4, lame},
+		{tag, 4, lame},
+		// The LAME header does not match the stream, which was edited.
+		{tag, 100, itunes},
+		{nil, 100, lame},
+	}
+	for i, tt := range tests {
+		got, ok := Gapless(tt.tag, analyze(tt.xingFrames))
+		if !ok || got != tt.want {
+			t.Errorf("%d: got %+v, %v, want %+v", i, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := Gapless(nil, &Info{Samples: 1152}); ok {
+		t.Error("gapless info without LAME header or iTunSMPB")
+	}
+}
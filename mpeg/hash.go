@@ -0,0 +1,156 @@
+package mpeg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+)
+
+// HashAudio feeds the MPEG audio frames of an MP3 file to h and returns the
+// resulting sum, so that copies of a file differing only in their tags hash
+// the same. A leading ID3v2 tag and the ID3v1, TAG+, APEv2, Lyrics3 and
+// appended ID3v2 tags at the end of the file are left out, as is the Xing,
+// Info or VBRI header frame when skipHeaderFrame is set, since it holds
+// counts and checksums that taggers may rewrite. It fails when no frame is
+// left to hash.
+func HashAudio(r io.ReadSeeker, h hash.Hash, skipHeaderFrame bool) ([]byte, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if end, err = audioEnd(r, end); err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	s, err := newScanner(io.LimitReader(r, end))
+	if err != nil {
+		return nil, err
+	}
+	first := true
+	hashed := 0
+	for {
+		f, err := s.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if first && skipHeaderFrame && new(Info).parseVBRHeaders(f) {
+			first = false
+			continue
+		}
+		first = false
+		h.Write(f.data)
+		hashed++
+	}
+	if hashed == 0 {
+		return nil, fmt.Errorf("no MPEG audio frames found")
+	}
+	return h.Sum(nil), nil
+}
+
+// audioEnd returns the position where the trailing tags of a file start,
+// removing them one after the other from its end.
+func audioEnd(r io.ReadSeeker, end int64) (int64, error) {
+	for {
+		n, err := trailingTagLen(r, end)
+		if err != nil {
+			return 0, err
+		}
+		if n == 0 || n > end {
+			return end, nil
+		}
+		end -= n
+	}
+}
+
+// trailingTagLen returns the length of the tag ending at end, or 0.
+func trailingTagLen(r io.ReadSeeker, end int64) (int64, error) {
+	read := func(offset int64, n int) ([]byte, error) {
+		if offset < 0 {
+			return nil, nil
+		}
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		data := make([]byte, n)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	// ID3v1, possibly preceded by the extended TAG+ block.
+	data, err := read(end-128, 128)
+	if err != nil {
+		return 0, err
+	}
+	if bytes.HasPrefix(data, []byte("TAG")) {
+		ext, err := read(end-128-227, 4)
+		if err != nil {
+			return 0, err
+		}
+		if bytes.Equal(ext, []byte("TAG+")) {
+			return 128 + 227, nil
+		}
+		return 128, nil
+	}
+
+	// APEv2, whose footer gives the size of the items and footer and tells
+	// whether a header precedes them.
+	data, err = read(end-32, 32)
+	if err != nil {
+		return 0, err
+	}
+	if bytes.HasPrefix(data, []byte("APETAGEX")) {
+		size := int64(binary.LittleEndian.Uint32(data[12:16]))
+		if binary.LittleEndian.Uint32(data[20:24])&(1<<31) != 0 {
+			size += 32
+		}
+		return size, nil
+	}
+
+	// ID3v2 appended with a footer.
+	data, err = read(end-10, 10)
+	if err != nil {
+		return 0, err
+	}
+	if bytes.HasPrefix(data, []byte("3DI")) {
+		size := int64(data[6])<<21 | int64(data[7])<<14 | int64(data[8])<<7 | int64(data[9])
+		return size + 20, nil
+	}
+
+	// Lyrics3v2 ends with its size as 6 digits, Lyrics3v1 has to be searched
+	// for its start within 5100 bytes of lyrics.
+	data, err = read(end-15, 15)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case bytes.HasSuffix(data, []byte("LYRICS200")):
+		size, err := strconv.ParseInt(string(data[:6]), 10, 64)
+		if err != nil {
+			return 0, nil
+		}
+		return size + 15, nil
+	case bytes.HasSuffix(data, []byte("LYRICSEND")):
+		start := end - 9 - 5100 - 11
+		if start < 0 {
+			start = 0
+		}
+		data, err := read(start, int(end-start))
+		if err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndex(data, []byte("LYRICSBEGIN")); i >= 0 {
+			return int64(len(data) - i), nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,77 @@
+package mpeg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+func TestHashAudio(t *testing.T) {
+	var audio bytes.Buffer
+	for i := 0; i < 5; i++ {
+		audio.Write(testFrame(header128, byte(i)))
+	}
+
+	ape := make([]byte, 64)
+	for _, b := range [][]byte{ape[:32], ape[32:]} {
+		copy(b, "APETAGEX")
+		binary.LittleEndian.PutUint32(b[8:], 2000)
+		binary.LittleEndian.PutUint32(b[12:], 32)
+		binary.LittleEndian.PutUint32(b[20:], 1<<31)
+	}
+	lyrics := "LYRICSBEGININD0000211"
+	lyrics += fmt.Sprintf("%06d", len(lyrics)) + "LYRICS200"
+	appended := append([]byte("ID3\x04\x00\x10\x00\x00\x00\x0A"), make([]byte, 10)...)
+	appended = append(appended, "3DI\x04\x00\x10\x00\x00\x00\x0A"...)
+	id3v1 := append([]byte("TAG"), make([]byte, 125)...)
+
+	files := map[string][]byte{
+		"bare":    audio.Bytes(),
+		"ID3v2":   append(testTag(t, &v2.ID3v2Frame{Id: "TIT2", Data: v2.NewTextFrame("Title")}), audio.Bytes()...),
+		"ID3v1":   append(append([]byte(nil), audio.Bytes()...), id3v1...),
+		"APEv2":   append(append([]byte(nil), audio.Bytes()...), ape...),
+		"Lyrics3": append(append(append([]byte(nil), audio.Bytes()...), lyrics...), id3v1...),
+		"stacked": bytes.Join([][]byte{audio.Bytes(), appended, ape, id3v1}, nil),
+		"TAG+":    bytes.Join([][]byte{audio.Bytes(), append([]byte("TAG+"), make([]byte, 223)...), id3v1}, nil),
+	}
+	want := sha256.Sum256(audio.Bytes())
+	for name, file := range files {
+		got, err := HashAudio(bytes.NewReader(file), sha256.New(), false)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if !bytes.Equal(got, want[:]) {
+			t.Errorf("%s: hash differs from the bare audio", name)
+		}
+	}
+
+	// Only the header frame differs, and is skipped on request.
+	a := append(xingFrame(true, 5, 3000, true, 576, 1260), audio.Bytes()...)
+	b := append(xingFrame(true, 6, 3500, true, 576, 1260), audio.Bytes()...)
+	hash := func(file []byte, skip bool) []byte {
+		sum, err := HashAudio(bytes.NewReader(file), sha256.New(), skip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sum
+	}
+	if bytes.Equal(hash(a, false), hash(b, false)) {
+		t.Error("header frames hashed the same")
+	}
+	if !bytes.Equal(hash(a, true), want[:]) || !bytes.Equal(hash(b, true), want[:]) {
+		t.Error("header frames not skipped")
+	}
+	for name, file := range map[string][]byte{
+		"empty":       nil,
+		"tag only":    testTag(t, &v2.ID3v2Frame{Id: "TIT2", Data: v2.NewTextFrame("Title")}),
+		"header only": xingFrame(true, 5, 3000, true, 576, 1260),
+	} {
+		if _, err := HashAudio(bytes.NewReader(file), sha256.New(), true); err == nil {
+			t.Errorf("%s: no error without audio frames", name)
+		}
+	}
+}
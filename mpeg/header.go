@@ -0,0 +1,246 @@
+package mpeg
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Version is the MPEG audio version of a frame.
+type Version int
+
+const (
+	Version1 Version = iota
+	Version2
+	Version25
+)
+
+func (v Version) String() string {
+	switch v {
+	case Version1:
+		return "MPEG-1"
+	case Version2:
+		return "MPEG-2"
+	case Version25:
+		return "MPEG-2.5"
+	}
+	return fmt.Sprintf("Version(%d)", int(v))
+}
+
+// Layer is the MPEG audio layer of a frame.
+type Layer int
+
+const (
+	Layer1 Layer = iota + 1
+	Layer2
+	Layer3
+)
+
+func (l Layer) String() string {
+	switch l {
+	case Layer1:
+		return "Layer I"
+	case Layer2:
+		return "Layer II"
+	case Layer3:
+		return "Layer III"
+	}
+	return fmt.Sprintf("Layer(%d)", int(l))
+}
+
+// ChannelMode is the channel mode of a frame.
+type ChannelMode int
+
+const (
+	Stereo ChannelMode = iota
+	JointStereo
+	DualChannel
+	Mono
+)
+
+func (c ChannelMode) String() string {
+	switch c {
+	case Stereo:
+		return "Stereo"
+	case JointStereo:
+		return "Joint stereo"
+	case DualChannel:
+		return "Dual channel"
+	case Mono:
+		return "Mono"
+	}
+	return fmt.Sprintf("ChannelMode(%d)", int(c))
+}
+
+// Channels returns the number of channels of the mode.
+func (c ChannelMode) Channels() int {
+	if c == Mono {
+		return 1
+	}
+	return 2
+}
+
+// Emphasis is the de-emphasis to apply when playing a frame.
+type Emphasis int
+
+const (
+	EmphasisNone Emphasis = iota
+	Emphasis50_15
+	EmphasisReserved
+	EmphasisCCITJ17
+)
+
+func (e Emphasis) String() string {
+	switch e {
+	case EmphasisNone:
+		return "None"
+	case Emphasis50_15:
+		return "50/15 ms"
+	case EmphasisCCITJ17:
+		return "CCIT J.17"
+	}
+	return "Reserved"
+}
+
+// HeaderLen is the length of an MPEG audio frame header.
+const HeaderLen = 4
+
+// bitrates lists the bitrates in kbit/s by bitrate index for MPEG-1 layers
+// I to III, then MPEG-2 and 2.5 layer I and layers II and III.
+var bitrates = [5][15]int{
+	{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+	{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+	{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+	{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+}
+
+var sampleRates = [3][3]int{
+	Version1:  {44100, 48000, 32000},
+	Version2:  {22050, 24000, 16000},
+	Version25: {11025, 12000, 8000},
+}
+
+// FrameHeader is a decoded MPEG audio frame header.
+//
+// Refer to http://www.mp3-tech.org/programmer/frame_header.html
+type FrameHeader struct {
+	Version       Version
+	Layer         Layer
+	Protected     bool
+	Bitrate       int
+	SampleRate    int
+	Padding       bool
+	Private       bool
+	ChannelMode   ChannelMode
+	ModeExtension int
+	Copyright     bool
+	Original      bool
+	Emphasis      Emphasis
+}
+
+// ParseFrameHeader decodes the 4 byte header of a frame. Free format
+// frames, whose bitrate is not given, are rejected as their size cannot be
+// told from the header.
+func ParseFrameHeader(data []byte) (FrameHeader, error) {
+	var h FrameHeader
+	if len(data) < HeaderLen {
+		return h, fmt.Errorf("frame header too short")
+	}
+	v := binary.BigEndian.Uint32(data)
+	if v>>21 != 0x7FF {
+		return h, fmt.Errorf("missing frame sync")
+	}
+	switch (v >> 19) & 3 {
+	case 0:
+		h.Version = Version25
+	case 2:
+		h.Version = Version2
+	case 3:
+		h.Version = Version1
+	default:
+		return h, fmt.Errorf("reserved MPEG version")
+	}
+	layer := (v >> 17) & 3
+	if layer == 0 {
+		return h, fmt.Errorf("reserved MPEG layer")
+	}
+	h.Layer = Layer(4 - layer)
+	h.Protected = (v>>16)&1 == 0
+
+	index := int((v >> 12) & 0xF)
+	if index == 0 || index == 0xF {
+		return h, fmt.Errorf("unsupported bitrate index: %d", index)
+	}
+	table := int(h.Layer) - 1
+	if h.Version != Version1 {
+		table = 4
+		if h.Layer == Layer1 {
+			table = 3
+		}
+	}
+	h.Bitrate = bitrates[table][index] * 1000
+
+	rate := int((v >> 10) & 3)
+	if rate == 3 {
+		return h, fmt.Errorf("reserved sample rate")
+	}
+	h.SampleRate = sampleRates[h.Version][rate]
+	h.Padding = (v>>9)&1 != 0
+	h.Private = (v>>8)&1 != 0
+	h.ChannelMode = ChannelMode((v >> 6) & 3)
+	h.ModeExtension = int((v >> 4) & 3)
+	h.Copyright = (v>>3)&1 != 0
+	h.Original = (v>>2)&1 != 0
+	h.Emphasis = Emphasis(v & 3)
+	if h.Emphasis == EmphasisReserved {
+		return h, fmt.Errorf("reserved emphasis")
+	}
+	return h, nil
+}
+
+// Samples returns the number of samples per channel in the frame.
+func (h FrameHeader) Samples() int {
+	switch {
+	case h.Layer == Layer1:
+		return 384
+	case h.Layer == Layer3 && h.Version != Version1:
+		return 576
+	}
+	return 1152
+}
+
+// Size returns the length of the frame including its header.
+func (h FrameHeader) Size() int {
+	if h.Layer == Layer1 {
+		size := 12 * h.Bitrate / h.SampleRate
+		if h.Padding {
+			size++
+		}
+		return size * 4
+	}
+	size := h.Samples() / 8 * h.Bitrate / h.SampleRate
+	if h.Padding {
+		size++
+	}
+	return size
+}
+
+// SideInfoLen returns the length of the Layer III side information which
+// follows the header and optional CRC, and 0 for other layers.
+func (h FrameHeader) SideInfoLen() int {
+	if h.Layer != Layer3 {
+		return 0
+	}
+	switch {
+	case h.Version == Version1 && h.ChannelMode != Mono:
+		return 32
+	case h.Version == Version1, h.ChannelMode != Mono:
+		return 17
+	}
+	return 9
+}
+
+// compatible reports whether two headers may belong to the same stream.
+func (h FrameHeader) compatible(o FrameHeader) bool {
+	return h.Version == o.Version && h.Layer == o.Layer && h.SampleRate == o.SampleRate
+}
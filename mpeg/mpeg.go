@@ -0,0 +1,228 @@
+// Package mpeg analyses the MPEG audio stream that follows the ID3v2 tag of
+// an MP3 file, without decoding it.
+package mpeg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+// Info describes an MPEG audio stream. The format fields come from the
+// first frame, Bitrate is the average bitrate in bit/s and VBR tells whether
+// frames have different bitrates or a VBR header says so.
+type Info struct {
+	Version     Version
+	Layer       Layer
+	Bitrate     int
+	SampleRate  int
+	ChannelMode ChannelMode
+	Emphasis    Emphasis
+	CRC         bool
+	VBR         bool
+	Frames      int
+	Samples     int64
+	Duration    time.Duration
+	// Xing, VBRI and LAME are the headers found in the first frame, which
+	// is then not counted as audio.
+	Xing *XingHeader
+	VBRI *VBRIHeader
+	LAME *LAMEHeader
+	// Offset is the position of the first audio frame in the file and Size
+	// the length of the audio frames up to the last one.
+	Offset int64
+	Size   int64
+	// LocationTable is the MLLT frame of the tag, used by OffsetForTime.
+	LocationTable *v2.LocationLookupFrame
+
+	// streamOffset is the position of the first frame, which may be a VBR
+	// header frame, and frameOffsets the position of each audio frame when
+	// they were all scanned.
+	streamOffset int64
+	frameSamples int
+	frameOffsets []int64
+}
+
+// Analyze reads an MP3 file, skipping a leading ID3v2 tag, and walks every
+// MPEG audio frame to measure the stream. See Probe for a faster estimate.
+// As with Probe, the MLLT frame of the tag is kept.
+func Analyze(r io.Reader) (*Info, error) {
+	s, err := newScanner(r)
+	if err != nil {
+		return nil, err
+	}
+	var info *Info
+	for {
+		f, err := s.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if info == nil {
+			info = newInfo(f)
+			info.LocationTable = locationTable(s.tag)
+			if info.parseVBRHeaders(f) {
+				// The header frame is silent and not part of the audio.
+				continue
+			}
+		}
+		if info.Frames == 0 {
+			info.Offset = f.offset
+			info.Bitrate = f.Bitrate
+		}
+		if f.Bitrate != info.Bitrate {
+			info.VBR = true
+		}
+		info.Frames++
+		info.Samples += int64(f.Samples())
+		info.Size = f.offset + int64(f.Size()) - info.Offset
+		info.frameOffsets = append(info.frameOffsets, f.offset)
+	}
+	if info == nil || info.Frames == 0 {
+		return nil, fmt.Errorf("no MPEG audio frames found")
+	}
+	info.Duration = samplesDuration(info.Samples, info.SampleRate)
+	if info.Duration > 0 {
+		info.Bitrate = int(float64(info.Size*8) / info.Duration.Seconds())
+	}
+	return info, nil
+}
+
+// newInfo describes the stream starting with the given frame.
+func newInfo(f *frame) *Info {
+	return &Info{
+		Version:      f.Version,
+		Layer:        f.Layer,
+		SampleRate:   f.SampleRate,
+		ChannelMode:  f.ChannelMode,
+		Emphasis:     f.Emphasis,
+		CRC:          f.Protected,
+		Bitrate:      f.Bitrate,
+		Offset:       f.offset,
+		streamOffset: f.offset,
+		frameSamples: f.Samples(),
+	}
+}
+
+// locationTable returns the MLLT frame of a tag, which may be nil.
+func locationTable(tag *v2.ID3v2Tag) *v2.LocationLookupFrame {
+	if tag == nil {
+		return nil
+	}
+	return tag.LocationLookupTable()
+}
+
+func samplesDuration(samples int64, sampleRate int) time.Duration {
+	return time.Duration(samples * int64(time.Second) / int64(sampleRate))
+}
+
+// frame is a frame found by a scanner. Data holds the whole frame and is
+// only valid until the next call to the scanner.
+type frame struct {
+	FrameHeader
+	offset int64
+	data   []byte
+}
+
+// scanner finds the successive frames of an MPEG audio stream. Tag is the
+// ID3v2 tag preceding the stream, or nil.
+type scanner struct {
+	r      *bufio.Reader
+	offset int64
+	first  *FrameHeader
+	tag    *v2.ID3v2Tag
+}
+
+// maxFrameLen bounds the length of a frame, reached by MPEG-2.5 Layer II
+// at 160 kbit/s and 8 kHz.
+const maxFrameLen = 2881
+
+func newScanner(r io.Reader) (*scanner, error) {
+	s := &scanner{r: bufio.NewReaderSize(r, 4*maxFrameLen)}
+	if err := s.readID3v2(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// readID3v2 reads the ID3v2 tag at the start of the stream, if any. The
+// tag is kept when it can be parsed.
+func (s *scanner) readID3v2() error {
+	data, err := s.r.Peek(3)
+	if err != nil || string(data) != "ID3" {
+		return nil
+	}
+	header, err := s.r.Peek(10)
+	if err != nil {
+		return err
+	}
+	header = append([]byte(nil), header...)
+	h, err := v2.ParseID3v2Header(s.r)
+	if err != nil {
+		return err
+	}
+	size := int64(h.Size)
+	if h.Footer {
+		size += 10
+	}
+	tag := make([]byte, 10+size)
+	copy(tag, header)
+	n, err := io.ReadFull(s.r, tag[10:])
+	s.offset += 10 + int64(n)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	s.tag, _ = v2.Read(bytes.NewReader(tag[:10+n]))
+	return nil
+}
+
+// next returns the next frame and moves past it. Garbage between frames is
+// skipped, only accepting a frame found that way when another one follows
+// it.
+func (s *scanner) next() (*frame, error) {
+	synced := true
+	for {
+		data, err := s.r.Peek(HeaderLen)
+		if err != nil {
+			return nil, err
+		}
+		h, err := ParseFrameHeader(data)
+		if err == nil && (s.first == nil || h.compatible(*s.first)) && (synced && s.first != nil || s.confirm(h)) {
+			data, err := s.r.Peek(h.Size())
+			if err != nil {
+				// The last frame is truncated.
+				return nil, io.EOF
+			}
+			if s.first == nil {
+				s.first = &h
+			}
+			f := &frame{FrameHeader: h, offset: s.offset, data: data}
+			s.r.Discard(h.Size())
+			s.offset += int64(h.Size())
+			return f, nil
+		}
+		synced = false
+		s.r.Discard(1)
+		s.offset++
+	}
+}
+
+// confirm reports whether a frame header found while searching for sync is
+// followed by a compatible one, or by the end of the stream.
+func (s *scanner) confirm(h FrameHeader) bool {
+	data, err := s.r.Peek(h.Size() + HeaderLen)
+	if len(data) == h.Size() && err != nil {
+		return s.first != nil
+	}
+	if err != nil {
+		return false
+	}
+	next, err := ParseFrameHeader(data[h.Size():])
+	return err == nil && next.compatible(h)
+}
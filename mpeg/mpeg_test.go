@@ -0,0 +1,130 @@
+package mpeg
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+// Headers of MPEG-1 Layer III frames at 44.1 kHz in stereo, 417 bytes long
+// at 128 kbit/s and 261 bytes at 80 kbit/s.
+var (
+	header128 = []byte{0xFF, 0xFB, 0x90, 0x00}
+	header80  = []byte{0xFF, 0xFB, 0x60, 0x00}
+)
+
+// testFrame returns a frame with the given header, filled with fill.
+func testFrame(header []byte, fill byte) []byte {
+	h, err := ParseFrameHeader(header)
+	if err != nil {
+		panic(err)
+	}
+	data := bytes.Repeat([]byte{fill}, h.Size())
+	copy(data, header)
+	return data
+}
+
+// testTag returns an ID3v2.4 tag holding the given frames.
+func testTag(t *testing.T, frames ...*v2.ID3v2Frame) []byte {
+	tag := v2.NewID3v2Tag(4)
+	for _, frame := range frames {
+		if err := tag.AddFrame(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := tag.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseFrameHeader(t *testing.T) {
+	tests := []struct {
+		header     []byte
+		version    Version
+		layer      Layer
+		bitrate    int
+		sampleRate int
+		mode       ChannelMode
+		samples    int
+		size       int
+	}{
+		{header128, Version1, Layer3, 128000, 44100, Stereo, 1152, 417},
+		// Padded joint stereo frame with a CRC.
+		{[]byte{0xFF, 0xFA, 0x92, 0x40}, Version1, Layer3, 128000, 44100, JointStereo, 1152, 418},
+		{[]byte{0xFF, 0xF3, 0x84, 0xC0}, Version2, Layer3, 64000, 24000, Mono, 576, 192},
+		{[]byte{0xFF, 0xE3, 0x18, 0xC0}, Version25, Layer3, 8000, 8000, Mono, 576, 72},
+		{[]byte{0xFF, 0xFD, 0xE0, 0x00}, Version1, Layer2, 384000, 44100, Stereo, 1152, 1253},
+		{[]byte{0xFF, 0xFF, 0x10, 0x00}, Version1, Layer1, 32000, 44100, Stereo, 384, 32},
+	}
+	for _, tt := range tests {
+		h, err := ParseFrameHeader(tt.header)
+		if err != nil {
+			t.Errorf("% X: %s", tt.header, err)
+			continue
+		}
+		if h.Version != tt.version || h.Layer != tt.layer || h.Bitrate != tt.bitrate ||
+			h.SampleRate != tt.sampleRate || h.ChannelMode != tt.mode ||
+			h.Samples() != tt.samples || h.Size() != tt.size {
+			t.Errorf("% X: got %+v, %d samples, %d bytes", tt.header, h, h.Samples(), h.Size())
+		}
+	}
+
+	for _, header := range [][]byte{
+		{0xFF, 0xFB},             // too short
+		{0xFF, 0x0B, 0x90, 0x00}, // no sync
+		{0xFF, 0xEB, 0x90, 0x00}, // reserved version
+		{0xFF, 0xF9, 0x90, 0x00}, // reserved layer
+		{0xFF, 0xFB, 0x00, 0x00}, // free format
+		{0xFF, 0xFB, 0xF0, 0x00}, // bad bitrate
+		{0xFF, 0xFB, 0x9C, 0x00}, // reserved sample rate
+		{0xFF, 0xFB, 0x90, 0x02}, // reserved emphasis
+	} {
+		if h, err := ParseFrameHeader(header); err == nil {
+			t.Errorf("% X: parsed as %+v", header, h)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tag := testTag(t, &v2.ID3v2Frame{Id: "TIT2", Data: v2.NewTextFrame("Title")})
+	var file bytes.Buffer
+	file.Write(tag)
+	// Garbage, including a false sync, before the first frame.
+	file.Write([]byte{0, 0xFF, 0xFB, 0x90, 0x00, 0})
+	for i := 0; i < 10; i++ {
+		header := header128
+		if i%2 == 1 {
+			header = header80
+		}
+		file.Write(testFrame(header, byte(i)))
+	}
+	file.Write(append([]byte("TAG"), make([]byte, 125)...))
+
+	info, err := Analyze(bytes.NewReader(file.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != Version1 || info.Layer != Layer3 || info.SampleRate != 44100 || info.ChannelMode != Stereo {
+		t.Errorf("format = %+v", info)
+	}
+	if info.Offset != int64(len(tag)+6) || info.Frames != 10 || info.Size != 5*417+5*261 || !info.VBR {
+		t.Errorf("offset %d, %d frames, size %d, VBR %v", info.Offset, info.Frames, info.Size, info.VBR)
+	}
+	if want := 11520 * time.Second / 44100; info.Samples != 11520 || info.Duration != want {
+		t.Errorf("%d samples, duration %s, want %s", info.Samples, info.Duration, want)
+	}
+	if info.Bitrate < 103000 || info.Bitrate > 105000 {
+		t.Errorf("bitrate = %d", info.Bitrate)
+	}
+	if got := info.OffsetForTime(info.Duration/2 + time.Millisecond); got != info.Offset+3*417+2*261 {
+		t.Errorf("offset of the sixth frame = %d", got)
+	}
+
+	if _, err := Analyze(bytes.NewReader(tag)); err == nil {
+		t.Error("tag without audio analysed")
+	}
+}
@@ -0,0 +1,129 @@
+package mpeg
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Probe measures an MP3 file from its ID3v2 tag and first frames only,
+// unlike Analyze which walks every frame. The frame count comes from the
+// Xing or VBRI header when there is one, and is otherwise estimated from
+// the file size assuming a constant bitrate. The MLLT frame of the tag is
+// kept for OffsetForTime.
+func Probe(r io.ReadSeeker) (*Info, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if end, err = audioEnd(r, end); err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	s, err := newScanner(io.LimitReader(r, end))
+	if err != nil {
+		return nil, err
+	}
+	f, err := s.next()
+	if err == io.EOF {
+		return nil, fmt.Errorf("no MPEG audio frames found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	info := newInfo(f)
+	info.LocationTable = locationTable(s.tag)
+	header := info.parseVBRHeaders(f)
+	headerSize := int64(f.Size())
+	if header {
+		if f, err = s.next(); err == nil {
+			info.Offset = f.offset
+			info.Bitrate = f.Bitrate
+		}
+	}
+	info.Size = end - info.Offset
+
+	switch {
+	case info.Xing != nil && info.Xing.Frames > 0:
+		info.Frames = int(info.Xing.Frames)
+		if info.Xing.Bytes > 0 {
+			info.Size = int64(info.Xing.Bytes) - headerSize
+		}
+	case info.VBRI != nil && info.VBRI.Frames > 0:
+		info.Frames = int(info.VBRI.Frames)
+		if info.VBRI.Bytes > 0 {
+			info.Size = int64(info.VBRI.Bytes) - headerSize
+		}
+	default:
+		frameSize := float64(info.frameSamples) / 8 * float64(info.Bitrate) / float64(info.SampleRate)
+		info.Frames = int(float64(info.Size)/frameSize + 0.5)
+	}
+	info.Samples = int64(info.Frames) * int64(info.frameSamples)
+	info.Duration = samplesDuration(info.Samples, info.SampleRate)
+	if info.Duration > 0 {
+		info.Bitrate = int(float64(info.Size*8) / info.Duration.Seconds())
+	}
+	return info, nil
+}
+
+// OffsetForTime returns the position in the file to start reading from to
+// play from d. It is exactly the frame holding d when Analyze recorded the
+// frame positions. Otherwise it is estimated from the MLLT frame, then the
+// Xing or VBRI table of contents, and finally the average bitrate, and
+// decoders have to resync to the next frame.
+func (info *Info) OffsetForTime(d time.Duration) int64 {
+	if d <= 0 || info.Duration <= 0 {
+		return info.Offset
+	}
+	if d >= info.Duration {
+		return info.Offset + info.Size
+	}
+	frame := int(int64(d) * int64(info.SampleRate) / int64(time.Second) / int64(info.frameSamples))
+
+	var offset int64
+	switch {
+	case len(info.frameOffsets) > 0:
+		if frame >= len(info.frameOffsets) {
+			frame = len(info.frameOffsets) - 1
+		}
+		return info.frameOffsets[frame]
+	case info.LocationTable != nil && len(info.LocationTable.Deviations) > 0:
+		// Interpolate between references at their nominal rate.
+		table := info.LocationTable
+		var t time.Duration
+		offset, t = table.Offset(d)
+		if table.MillisecondsBetween > 0 {
+			offset += int64((d - t).Seconds() * 1000 * float64(table.BytesBetween) / float64(table.MillisecondsBetween))
+		}
+		offset += info.streamOffset
+	case info.Xing != nil && len(info.Xing.TOC) == 100 && info.Xing.Bytes > 0:
+		percent := float64(d) / float64(info.Duration) * 100
+		i := int(percent)
+		a, b := float64(info.Xing.TOC[i]), 256.0
+		if i < 99 {
+			b = float64(info.Xing.TOC[i+1])
+		}
+		position := a + (b-a)*(percent-float64(i))
+		offset = info.streamOffset + int64(position/256*float64(info.Xing.Bytes))
+	case info.VBRI != nil && len(info.VBRI.TOC) > 0 && info.VBRI.FramesPerEntry > 0:
+		offset = info.Offset
+		for _, size := range info.VBRI.TOC {
+			if frame < int(info.VBRI.FramesPerEntry) {
+				break
+			}
+			offset += int64(size)
+			frame -= int(info.VBRI.FramesPerEntry)
+		}
+	default:
+		offset = info.Offset + int64(d.Seconds()*float64(info.Bitrate)/8)
+	}
+	if offset < info.Offset {
+		return info.Offset
+	}
+	if offset > info.Offset+info.Size {
+		return info.Offset + info.Size
+	}
+	return offset
+}
@@ -0,0 +1,52 @@
+package mpeg
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+func TestLocationTable(t *testing.T) {
+	table := &v2.LocationLookupFrame{
+		FramesBetween: 2, BytesBetween: 834, MillisecondsBetween: 52,
+		BytesBits: 8, MillisecondsBits: 8,
+		Deviations: []v2.LocationDeviation{{}, {}, {Milliseconds: 1}, {}},
+	}
+	tag := testTag(t, &v2.ID3v2Frame{Id: "MLLT", Data: table})
+	var file bytes.Buffer
+	file.Write(tag)
+	for i := 0; i < 10; i++ {
+		file.Write(testFrame(header128, byte(i)))
+	}
+
+	analyzed, err := Analyze(bytes.NewReader(file.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	probed, err := Probe(bytes.NewReader(file.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, info := range map[string]*Info{"Analyze": analyzed, "Probe": probed} {
+		if !reflect.DeepEqual(info.LocationTable, table) {
+			t.Errorf("%s: location table = %+v", name, info.LocationTable)
+		}
+		if info.Offset != int64(len(tag)) || info.Frames != 10 {
+			t.Errorf("%s: %d frames at %d", name, info.Frames, info.Offset)
+		}
+	}
+
+	// Probe interpolates from the last reference, two frames before.
+	d := 110 * time.Millisecond
+	want := int64(len(tag)) + 2*834 + int64((d-104*time.Millisecond).Seconds()*1000*834/52)
+	if got := probed.OffsetForTime(d); got != want {
+		t.Errorf("Probe: offset for %s = %d, want %d", d, got, want)
+	}
+	// Analyze knows the position of every frame.
+	if got := analyzed.OffsetForTime(d); got != int64(len(tag))+4*417 {
+		t.Errorf("Analyze: offset for %s = %d", d, got)
+	}
+}
@@ -0,0 +1,246 @@
+package mpeg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+// Flags of the Xing header telling which fields are present.
+const (
+	xingFrames  = 0x1
+	xingBytes   = 0x2
+	xingTOC     = 0x4
+	xingQuality = 0x8
+)
+
+// XingHeader is the Xing header written in the first frame of VBR streams,
+// or the Info header written by LAME for CBR streams. Frames is the number
+// of audio frames and Bytes the length of the stream including the header
+// frame, both 0 when absent. TOC maps each percent of the duration to the
+// position in the stream in 1/256 of Bytes.
+//
+// Refer to http://gabriel.mp3-tech.org/mp3infotag.html
+type XingHeader struct {
+	VBR     bool
+	Frames  uint32
+	Bytes   uint32
+	TOC     []byte
+	Quality uint32
+}
+
+// VBRIHeader is the VBR header written by the Fraunhofer encoder, 32 bytes
+// after the header of the first frame. TOC holds the byte length of each
+// group of FramesPerEntry frames.
+type VBRIHeader struct {
+	Version        uint16
+	Delay          uint16
+	Quality        uint16
+	Bytes          uint32
+	Frames         uint32
+	FramesPerEntry uint16
+	TOC            []uint32
+}
+
+// VBRMethod is the bitrate mode LAME encoded with.
+type VBRMethod int
+
+const (
+	VBRUnknown VBRMethod = iota
+	VBRConstant
+	VBRAverage
+	VBROld
+	VBRMTRH
+	VBRMT
+	VBRMethod4
+	_
+	VBRConstant2Pass
+	VBRAverage2Pass
+)
+
+// LAMEHeader is the LAME extension of the Xing or Info header.
+//
+// Refer to http://gabriel.mp3-tech.org/mp3infotag.html
+type LAMEHeader struct {
+	Encoder  string
+	Revision int
+	Method   VBRMethod
+	// Lowpass is the lowpass filter frequency in Hz, 0 when unknown.
+	Lowpass int
+	// ReplayGain holds the radio gain as track gain and the audiophile gain
+	// as album gain, with the peak amplitude of the track.
+	ReplayGain    v2.ReplayGain
+	EncodingFlags byte
+	ATHType       int
+	// Bitrate is the ABR target, or the minimum bitrate for VBR, in kbit/s.
+	Bitrate      int
+	EncoderDelay int
+	Padding      int
+	Preset       int
+	MusicLength  uint32
+	MusicCRC     uint16
+	TagCRC       uint16
+	// TagCRCValid tells whether TagCRC matches the first 190 bytes of the
+	// frame.
+	TagCRCValid bool
+}
+
+// parseVBRHeaders looks for a Xing, Info or VBRI header in the first frame,
+// reporting whether one was found.
+func (info *Info) parseVBRHeaders(f *frame) bool {
+	offset := HeaderLen + f.SideInfoLen()
+	if f.Protected {
+		offset += 2
+	}
+	if xing, lame := parseXing(f.data, offset); xing != nil {
+		info.Xing, info.LAME = xing, lame
+		info.VBR = xing.VBR
+		return true
+	}
+	if vbri := parseVBRI(f.data, HeaderLen+32); vbri != nil {
+		info.VBRI = vbri
+		info.VBR = true
+		return true
+	}
+	return false
+}
+
+func parseXing(data []byte, offset int) (*XingHeader, *LAMEHeader) {
+	if len(data) < offset+8 {
+		return nil, nil
+	}
+	id := string(data[offset : offset+4])
+	if id != "Xing" && id != "Info" {
+		return nil, nil
+	}
+	x := &XingHeader{VBR: id == "Xing"}
+	flags := binary.BigEndian.Uint32(data[offset+4:])
+	pos := offset + 8
+	field := func(n int) []byte {
+		if len(data) < pos+n {
+			return nil
+		}
+		b := data[pos : pos+n]
+		pos += n
+		return b
+	}
+	if flags&xingFrames != 0 {
+		if b := field(4); b != nil {
+			x.Frames = binary.BigEndian.Uint32(b)
+		}
+	}
+	if flags&xingBytes != 0 {
+		if b := field(4); b != nil {
+			x.Bytes = binary.BigEndian.Uint32(b)
+		}
+	}
+	if flags&xingTOC != 0 {
+		// The frame data is reused by the scanner.
+		x.TOC = append([]byte(nil), field(100)...)
+	}
+	if flags&xingQuality != 0 {
+		if b := field(4); b != nil {
+			x.Quality = binary.BigEndian.Uint32(b)
+		}
+	}
+	return x, parseLAME(data, pos)
+}
+
+// lameLen is the length of the LAME extension.
+const lameLen = 36
+
+func parseLAME(data []byte, offset int) *LAMEHeader {
+	if len(data) < offset+lameLen {
+		return nil
+	}
+	b := data[offset : offset+lameLen]
+	if !bytes.HasPrefix(b, []byte("LAME")) && !bytes.HasPrefix(b, []byte("Lav")) && !bytes.HasPrefix(b, []byte("GOGO")) {
+		return nil
+	}
+	l := &LAMEHeader{
+		Encoder:       strings.TrimRight(string(b[:9]), "\x00 "),
+		Revision:      int(b[9] >> 4),
+		Method:        VBRMethod(b[9] & 0x0F),
+		Lowpass:       int(b[10]) * 100,
+		EncodingFlags: b[19] >> 4,
+		ATHType:       int(b[19] & 0x0F),
+		Bitrate:       int(b[20]),
+		EncoderDelay:  int(b[21])<<4 | int(b[22])>>4,
+		Padding:       int(b[22]&0x0F)<<8 | int(b[23]),
+		Preset:        int(binary.BigEndian.Uint16(b[26:28]) & 0x07FF),
+		MusicLength:   binary.BigEndian.Uint32(b[28:32]),
+		MusicCRC:      binary.BigEndian.Uint16(b[32:34]),
+		TagCRC:        binary.BigEndian.Uint16(b[34:36]),
+	}
+	l.TagCRCValid = crc16(data[:offset+34]) == l.TagCRC
+
+	peak := float64(binary.BigEndian.Uint32(b[11:15])) / math.Exp2(23)
+	for _, field := range [][]byte{b[15:17], b[17:19]} {
+		v := binary.BigEndian.Uint16(field)
+		name := v >> 13
+		if name != 1 && name != 2 {
+			continue
+		}
+		gain := &v2.Gain{Adjustment: float64(v&0x01FF) / 10}
+		if v&0x0200 != 0 {
+			gain.Adjustment = -gain.Adjustment
+		}
+		if name == 1 {
+			gain.Peak = peak
+			l.ReplayGain.Track = gain
+		} else {
+			l.ReplayGain.Album = gain
+		}
+	}
+	return l
+}
+
+func parseVBRI(data []byte, offset int) *VBRIHeader {
+	if len(data) < offset+26 || string(data[offset:offset+4]) != "VBRI" {
+		return nil
+	}
+	b := data[offset:]
+	v := &VBRIHeader{
+		Version:        binary.BigEndian.Uint16(b[4:6]),
+		Delay:          binary.BigEndian.Uint16(b[6:8]),
+		Quality:        binary.BigEndian.Uint16(b[8:10]),
+		Bytes:          binary.BigEndian.Uint32(b[10:14]),
+		Frames:         binary.BigEndian.Uint32(b[14:18]),
+		FramesPerEntry: binary.BigEndian.Uint16(b[24:26]),
+	}
+	entries := int(binary.BigEndian.Uint16(b[18:20]))
+	scale := uint32(binary.BigEndian.Uint16(b[20:22]))
+	size := int(binary.BigEndian.Uint16(b[22:24]))
+	b = b[26:]
+	if size < 1 || size > 4 || len(b) < entries*size {
+		return v
+	}
+	for i := 0; i < entries; i++ {
+		entry := uint32(0)
+		for _, c := range b[i*size : (i+1)*size] {
+			entry = entry<<8 | uint32(c)
+		}
+		v.TOC = append(v.TOC, entry*scale)
+	}
+	return v
+}
+
+// crc16 computes the CRC-16 used by the LAME tag, with the reversed
+// polynomial 0xA001 and no initial value.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
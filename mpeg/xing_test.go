@@ -0,0 +1,140 @@
+package mpeg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// xingFrame returns an Info or Xing header frame, with a LAME extension
+// giving the encoder delay and padding when lame is set.
+func xingFrame(vbr bool, frames, size uint32, lame bool, delay, padding int) []byte {
+	data := testFrame(header128, 0)
+	pos := HeaderLen + 32
+	id := "Info"
+	if vbr {
+		id = "Xing"
+	}
+	copy(data[pos:], id)
+	binary.BigEndian.PutUint32(data[pos+4:], xingFrames|xingBytes|xingTOC|xingQuality)
+	binary.BigEndian.PutUint32(data[pos+8:], frames)
+	binary.BigEndian.PutUint32(data[pos+12:], size)
+	for i := 0; i < 100; i++ {
+		data[pos+16+i] = byte(i * 256 / 100)
+	}
+	binary.BigEndian.PutUint32(data[pos+116:], 78)
+	if !lame {
+		return data
+	}
+	pos += 120
+	b := data[pos : pos+lameLen]
+	copy(b, "LAME3.100")
+	b[9] = 0x1<<4 | byte(VBRMTRH)
+	b[10] = 195
+	binary.BigEndian.PutUint32(b[11:], uint32(0.5*math.Exp2(23)))
+	// Radio gain of -6.3 dB and audiophile gain of +1.5 dB, set by the user.
+	binary.BigEndian.PutUint16(b[15:], 1<<13|2<<10|0x200|63)
+	binary.BigEndian.PutUint16(b[17:], 2<<13|2<<10|15)
+	b[19] = 0x14
+	b[20] = 128
+	b[21], b[22], b[23] = byte(delay>>4), byte(delay<<4)|byte(padding>>8), byte(padding)
+	binary.BigEndian.PutUint16(b[26:], 1001)
+	binary.BigEndian.PutUint32(b[28:], size)
+	binary.BigEndian.PutUint16(b[34:], crc16(data[:pos+34]))
+	return data
+}
+
+func TestCRC16(t *testing.T) {
+	// The check value of CRC-16/ARC.
+	if got := crc16([]byte("123456789")); got != 0xBB3D {
+		t.Errorf("got %04X, want BB3D", got)
+	}
+}
+
+func TestXingHeader(t *testing.T) {
+	header := xingFrame(true, 1000, 400000, true, 576, 1260)
+	var file bytes.Buffer
+	file.Write(header)
+	for i := 0; i < 3; i++ {
+		file.Write(testFrame(header128, 1))
+	}
+
+	info, err := Analyze(bytes.NewReader(file.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Frames != 3 || info.Offset != int64(len(header)) || !info.VBR {
+		t.Errorf("%d frames at %d, VBR %v", info.Frames, info.Offset, info.VBR)
+	}
+	x := info.Xing
+	if x == nil || x.Frames != 1000 || x.Bytes != 400000 || len(x.TOC) != 100 || x.TOC[50] != 128 || x.Quality != 78 {
+		t.Fatalf("Xing header = %+v", x)
+	}
+	l := info.LAME
+	if l == nil {
+		t.Fatal("no LAME header")
+	}
+	if l.Encoder != "LAME3.100" || l.Revision != 1 || l.Method != VBRMTRH || l.Lowpass != 19500 ||
+		l.Bitrate != 128 || l.EncoderDelay != 576 || l.Padding != 1260 || l.Preset != 1001 ||
+		l.EncodingFlags != 1 || l.ATHType != 4 || l.MusicLength != 400000 {
+		t.Errorf("LAME header = %+v", l)
+	}
+	if !l.TagCRCValid {
+		t.Error("tag CRC not valid")
+	}
+	rg := l.ReplayGain
+	if rg.Track == nil || rg.Track.Adjustment != -6.3 || rg.Track.Peak != 0.5 || rg.Album == nil || rg.Album.Adjustment != 1.5 {
+		t.Errorf("ReplayGain = %+v %+v", rg.Track, rg.Album)
+	}
+
+	// Probe trusts the frame count of the header.
+	probed, err := Probe(bytes.NewReader(file.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if probed.Frames != 1000 || probed.Size != 400000-int64(len(header)) {
+		t.Errorf("probed %d frames, size %d", probed.Frames, probed.Size)
+	}
+
+	// A changed byte covered by the CRC is detected.
+	corrupt := append([]byte(nil), header...)
+	corrupt[HeaderLen+40]++
+	info = new(Info)
+	f := &frame{data: corrupt}
+	f.FrameHeader, _ = ParseFrameHeader(corrupt)
+	if !info.parseVBRHeaders(f) || info.LAME == nil || info.LAME.TagCRCValid {
+		t.Errorf("corrupt LAME header = %+v", info.LAME)
+	}
+}
+
+func TestVBRIHeader(t *testing.T) {
+	data := testFrame(header128, 0)
+	b := data[HeaderLen+32:]
+	copy(b, "VBRI")
+	binary.BigEndian.PutUint16(b[4:], 1)
+	binary.BigEndian.PutUint16(b[6:], 1000)
+	binary.BigEndian.PutUint16(b[8:], 75)
+	binary.BigEndian.PutUint32(b[10:], 50000)
+	binary.BigEndian.PutUint32(b[14:], 120)
+	binary.BigEndian.PutUint16(b[18:], 3)
+	binary.BigEndian.PutUint16(b[20:], 2)
+	binary.BigEndian.PutUint16(b[22:], 2)
+	binary.BigEndian.PutUint16(b[24:], 40)
+	for i, size := range []uint16{8000, 8500, 8200} {
+		binary.BigEndian.PutUint16(b[26+2*i:], size)
+	}
+
+	info := new(Info)
+	f := &frame{data: data}
+	f.FrameHeader, _ = ParseFrameHeader(data)
+	if !info.parseVBRHeaders(f) || info.Xing != nil || !info.VBR {
+		t.Fatalf("VBRI header not found: %+v", info)
+	}
+	v := info.VBRI
+	want := []uint32{16000, 17000, 16400}
+	if v.Version != 1 || v.Delay != 1000 || v.Quality != 75 || v.Bytes != 50000 || v.Frames != 120 ||
+		v.FramesPerEntry != 40 || len(v.TOC) != 3 || v.TOC[0] != want[0] || v.TOC[1] != want[1] || v.TOC[2] != want[2] {
+		t.Errorf("VBRI header = %+v", v)
+	}
+}
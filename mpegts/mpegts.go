@@ -0,0 +1,365 @@
+// Package mpegts extracts the ID3v2 tags carried as timed metadata in the
+// PES packets of an MPEG-2 transport stream, as in HTTP Live Streaming
+// segments. Metadata streams are found through the program map tables:
+// streams of type 0x15 (metadata in PES packets) whose metadata descriptor,
+// when present, announces the "ID3 " format.
+//
+// Refer to ISO/IEC 13818-1 and Apple's Timed Metadata for HTTP Live
+// Streaming.
+package mpegts
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+const (
+	// PacketSize is the length of a transport stream packet.
+	PacketSize = 188
+
+	syncByte = 0x47
+
+	// streamTypeMetadata is the stream type of metadata carried in PES
+	// packets.
+	streamTypeMetadata = 0x15
+	// descriptorMetadata is the tag of the metadata descriptor.
+	descriptorMetadata = 0x26
+
+	// ClockRate is the frequency of presentation timestamps.
+	ClockRate = 90000
+)
+
+// Metadata is an ID3v2 tag read from a metadata stream. PTS is the
+// presentation timestamp of its PES packet, in ClockRate units, when
+// HasPTS is set. Err is set, and Tag nil, for a PES packet or tag that
+// could not be read.
+type Metadata struct {
+	PID    uint16
+	PTS    uint64
+	HasPTS bool
+	Tag    *v2.ID3v2Tag
+	Err    error
+}
+
+// Time returns the presentation timestamp as a duration.
+func (m *Metadata) Time() time.Duration {
+	return time.Duration(m.PTS) * time.Second / ClockRate
+}
+
+// Timestamp returns the MPEG-2 timestamp of the first sample of the segment
+// held by the com.apple.streaming.transportStreamTimestamp PRIV frame of
+// the tag, in ClockRate units.
+func (m *Metadata) Timestamp() (uint64, bool) {
+	if m.Tag == nil {
+		return 0, false
+	}
+	for _, frame := range m.Tag.Frames {
+		p, ok := frame.Data.(*v2.PrivateFrame)
+		if !ok || p.Owner != v2.TransportStreamTimestampOwner {
+			continue
+		}
+		if ts, ok := p.Value.(uint64); ok {
+			return ts, true
+		}
+	}
+	return 0, false
+}
+
+// pes is a PES packet being reassembled from transport stream packets.
+type pes struct {
+	data []byte
+}
+
+// Reader demultiplexes a transport stream and returns the ID3v2 tags of its
+// metadata streams.
+type Reader struct {
+	r        *bufio.Reader
+	packet   []byte
+	pmtPIDs  map[uint16]bool
+	sections map[uint16][]byte
+	streams  map[uint16]*pes
+	pending  []*Metadata
+	eof      bool
+}
+
+// NewReader returns a Reader demultiplexing the transport stream read from
+// r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		r:        bufio.NewReader(r),
+		packet:   make([]byte, PacketSize),
+		pmtPIDs:  make(map[uint16]bool),
+		sections: make(map[uint16][]byte),
+		streams:  make(map[uint16]*pes),
+	}
+}
+
+// Next returns the next tag of the stream, in stream order, or io.EOF when
+// there are no more. PES packets of unbounded length are returned once the
+// next one starts or the stream ends. A bad PES packet or tag is returned
+// with Err set, demultiplexing going on with the next one.
+func (r *Reader) Next() (*Metadata, error) {
+	for len(r.pending) == 0 {
+		if r.eof {
+			return nil, io.EOF
+		}
+		if err := r.readPacket(); err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			r.eof = true
+			pids := make([]uint16, 0, len(r.streams))
+			for pid := range r.streams {
+				pids = append(pids, pid)
+			}
+			sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+			for _, pid := range pids {
+				r.flush(pid)
+			}
+		}
+	}
+	m := r.pending[0]
+	r.pending = r.pending[1:]
+	return m, nil
+}
+
+// ReadAll returns all the tags of the transport stream read from r, leaving
+// out those that could not be read.
+func ReadAll(r io.Reader) ([]*Metadata, error) {
+	var tags []*Metadata
+	tr := NewReader(r)
+	for {
+		m, err := tr.Next()
+		if err == io.EOF {
+			return tags, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if m.Err == nil {
+			tags = append(tags, m)
+		}
+	}
+}
+
+// readPacket reads and handles one transport stream packet, skipping
+// garbage up to the next sync byte.
+func (r *Reader) readPacket() error {
+	for {
+		b, err := r.r.Peek(1)
+		if err != nil {
+			return err
+		}
+		if b[0] == syncByte {
+			break
+		}
+		if _, err := r.r.Discard(1); err != nil {
+			return err
+		}
+	}
+	if _, err := io.ReadFull(r.r, r.packet); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return io.EOF
+		}
+		return err
+	}
+
+	p := r.packet
+	transportError := p[1]&0x80 != 0
+	start := p[1]&0x40 != 0
+	pid := uint16(p[1]&0x1F)<<8 | uint16(p[2])
+	control := p[3] >> 4 & 3
+	if transportError || control&1 == 0 {
+		return nil
+	}
+	payload := p[4:]
+	if control&2 != 0 {
+		n := int(payload[0]) + 1
+		if n > len(payload) {
+			return nil
+		}
+		payload = payload[n:]
+	}
+
+	switch {
+	case pid == 0 || r.pmtPIDs[pid]:
+		r.readSection(pid, start, payload)
+	case r.streams[pid] != nil:
+		s := r.streams[pid]
+		if start {
+			r.flush(pid)
+			s.data = append(s.data[:0], payload...)
+		} else if len(s.data) > 0 {
+			s.data = append(s.data, payload...)
+		}
+		if n := pesLength(s.data); n > 0 && len(s.data) >= n {
+			s.data = s.data[:n]
+			r.flush(pid)
+		}
+	}
+	return nil
+}
+
+// readSection reassembles the PAT and PMT sections.
+func (r *Reader) readSection(pid uint16, start bool, payload []byte) {
+	if start && len(payload) > 0 {
+		pointer := int(payload[0]) + 1
+		if pointer > len(payload) {
+			return
+		}
+		r.sections[pid] = append(r.sections[pid][:0], payload[pointer:]...)
+	} else if len(r.sections[pid]) > 0 {
+		r.sections[pid] = append(r.sections[pid], payload...)
+	}
+	section := r.sections[pid]
+	if len(section) < 3 {
+		return
+	}
+	n := 3 + (int(section[1]&0x0F)<<8 | int(section[2]))
+	if len(section) < n {
+		return
+	}
+	r.sections[pid] = section[:0]
+	// Skip the header after the length and the trailing CRC.
+	if n < 12 {
+		return
+	}
+	body := section[8 : n-4]
+	switch section[0] {
+	case 0x00:
+		r.readPAT(body)
+	case 0x02:
+		r.readPMT(body)
+	}
+}
+
+func (r *Reader) readPAT(body []byte) {
+	for ; len(body) >= 4; body = body[4:] {
+		program := uint16(body[0])<<8 | uint16(body[1])
+		pid := uint16(body[2]&0x1F)<<8 | uint16(body[3])
+		if program != 0 {
+			r.pmtPIDs[pid] = true
+		}
+	}
+}
+
+func (r *Reader) readPMT(body []byte) {
+	if len(body) < 4 {
+		return
+	}
+	n := 4 + (int(body[2]&0x0F)<<8 | int(body[3]))
+	if n > len(body) {
+		return
+	}
+	for body = body[n:]; len(body) >= 5; {
+		streamType := body[0]
+		pid := uint16(body[1]&0x1F)<<8 | uint16(body[2])
+		n := 5 + (int(body[3]&0x0F)<<8 | int(body[4]))
+		if n > len(body) {
+			return
+		}
+		if streamType == streamTypeMetadata && isID3(body[5:n]) && r.streams[pid] == nil {
+			r.streams[pid] = &pes{}
+		}
+		body = body[n:]
+	}
+}
+
+// isID3 tells whether the elementary stream descriptors do not announce a
+// metadata format other than ID3.
+func isID3(descriptors []byte) bool {
+	for len(descriptors) >= 2 {
+		tag, n := descriptors[0], 2+int(descriptors[1])
+		if n > len(descriptors) {
+			break
+		}
+		d := descriptors[2:n]
+		descriptors = descriptors[n:]
+		if tag != descriptorMetadata || len(d) < 2 {
+			continue
+		}
+		i := 2
+		if d[0] == 0xFF && d[1] == 0xFF {
+			i += 4
+		}
+		if i >= len(d) {
+			continue
+		}
+		if d[i] != 0xFF || i+5 > len(d) {
+			return false
+		}
+		return string(d[i+1:i+5]) == "ID3 "
+	}
+	return true
+}
+
+// pesLength returns the length of the PES packet starting data, or 0 when
+// unbounded or not known yet.
+func pesLength(data []byte) int {
+	if len(data) < 6 {
+		return 0
+	}
+	n := int(data[4])<<8 | int(data[5])
+	if n == 0 {
+		return 0
+	}
+	return 6 + n
+}
+
+// flush parses the PES packet reassembled for pid and queues its tags, or
+// an error for a bad packet or tag.
+func (r *Reader) flush(pid uint16) {
+	s := r.streams[pid]
+	data := s.data
+	s.data = s.data[:0]
+	if len(data) < 9 || !bytes.HasPrefix(data, []byte{0, 0, 1}) {
+		return
+	}
+	m := &Metadata{PID: pid}
+	headerLen := 9 + int(data[8])
+	if headerLen > len(data) {
+		m.Err = fmt.Errorf("truncated PES header on PID %d", pid)
+		r.pending = append(r.pending, m)
+		return
+	}
+	if data[7]&0x80 != 0 && headerLen >= 14 {
+		m.PTS = parseTimestamp(data[9:14])
+		m.HasPTS = true
+	}
+	payload := data[headerLen:]
+
+	// A PES packet may hold several tags back to back.
+	for len(payload) >= 10 && bytes.HasPrefix(payload, []byte("ID3")) {
+		size := 10 + (int(payload[6])<<21 | int(payload[7])<<14 | int(payload[8])<<7 | int(payload[9]))
+		if payload[5]&0x10 != 0 {
+			size += 10
+		}
+		if size > len(payload) {
+			size = len(payload)
+		}
+		tm := *m
+		if tag, err := v2.Read(bytes.NewReader(payload[:size])); err != nil {
+			tm.Err = fmt.Errorf("invalid ID3v2 tag on PID %d: %s", pid, err)
+		} else {
+			tm.Tag = tag
+		}
+		r.pending = append(r.pending, &tm)
+		payload = payload[size:]
+	}
+}
+
+// parseTimestamp decodes a 33 bit PTS or DTS spread over 5 bytes with
+// marker bits.
+func parseTimestamp(data []byte) uint64 {
+	return uint64(data[0]>>1&0x07)<<30 |
+		uint64(data[1])<<22 |
+		uint64(data[2]>>1)<<15 |
+		uint64(data[3])<<7 |
+		uint64(data[4]>>1)
+}
@@ -0,0 +1,161 @@
+package mpegts
+
+import (
+	"bytes"
+	"testing"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+const (
+	testPMTPID      = 0x100
+	testMetadataPID = 0x101
+)
+
+// packet returns a transport stream packet carrying payload, stuffed with
+// an adaptation field when shorter than a packet.
+func packet(pid uint16, start bool, payload []byte) []byte {
+	p := []byte{syncByte, byte(pid >> 8), byte(pid), 0x10}
+	if start {
+		p[1] |= 0x40
+	}
+	if n := PacketSize - 4 - len(payload); n > 0 {
+		p[3] = 0x30
+		p = append(p, byte(n-1))
+		if n > 1 {
+			p = append(p, 0)
+			p = append(p, bytes.Repeat([]byte{0xFF}, n-2)...)
+		}
+	}
+	return append(p, payload...)
+}
+
+// packets splits a PES packet over transport stream packets.
+func packets(pid uint16, pes []byte) []byte {
+	var ts []byte
+	for start := true; len(pes) > 0; start = false {
+		n := min(len(pes), PacketSize-4)
+		ts = append(ts, packet(pid, start, pes[:n])...)
+		pes = pes[n:]
+	}
+	return ts
+}
+
+// section returns a PSI section with a dummy CRC, preceded by its pointer
+// field.
+func section(table byte, body []byte) []byte {
+	n := 5 + len(body) + 4
+	s := []byte{0, table, 0xB0 | byte(n>>8), byte(n), 0, 1, 0xC1, 0, 0}
+	s = append(s, body...)
+	return append(s, 0, 0, 0, 0)
+}
+
+// programTables returns a PAT and a PMT announcing an ID3 metadata stream.
+func programTables() []byte {
+	pat := section(0x00, []byte{0, 1, 0xE0 | testPMTPID>>8, testPMTPID & 0xFF})
+	descriptor := append([]byte{descriptorMetadata, 11, 0xFF, 0xFF}, "ID3 \xFFID3 "...)
+	pmt := section(0x02, append([]byte{
+		0xE0 | testMetadataPID>>8, testMetadataPID & 0xFF, 0xF0, 0,
+		streamTypeMetadata, 0xE0 | testMetadataPID>>8, testMetadataPID & 0xFF, 0xF0, byte(len(descriptor)),
+	}, descriptor...))
+	return append(packet(0, true, pat), packet(testPMTPID, true, pmt)...)
+}
+
+// pesPacket returns a bounded private stream PES packet with a PTS.
+func pesPacket(pts uint64, payload []byte) []byte {
+	header := []byte{
+		0x84, 0x80, 5,
+		0x21 | byte(pts>>29)&0x0E, byte(pts >> 22), byte(pts>>14) | 1, byte(pts >> 7), byte(pts<<1) | 1,
+	}
+	n := len(header) + len(payload)
+	p := append([]byte{0, 0, 1, 0xBD, byte(n >> 8), byte(n)}, header...)
+	return append(p, payload...)
+}
+
+func testTag(t *testing.T, timestamp uint64) []byte {
+	raw := append([]byte(v2.TransportStreamTimestampOwner+"\x00"), 0, 0, 0, 0, 0, 0, 0, 0)
+	for i := 0; i < 8; i++ {
+		raw[len(raw)-1-i] = byte(timestamp >> (8 * i))
+	}
+	priv, err := v2.ParsePrivateFrame(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := v2.NewID3v2Tag(4)
+	tag.AddFrame(&v2.ID3v2Frame{Id: "PRIV", Data: priv})
+	tag.SetTextValues("TIT2", "Segment")
+	data, err := tag.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseTimestamp(t *testing.T) {
+	for _, pts := range []uint64{0, 1, 90000, 0x123456789, 1<<33 - 1} {
+		data := pesPacket(pts, nil)[9:14]
+		if got := parseTimestamp(data); got != pts {
+			t.Errorf("%d decoded as %d", pts, got)
+		}
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	first, second := testTag(t, 900000), testTag(t, 1<<32)
+	var ts []byte
+	ts = append(ts, programTables()...)
+	// Two tags in one PES packet spanning several transport stream packets.
+	ts = append(ts, packets(testMetadataPID, pesPacket(0x123456789, append(first, first...)))...)
+	// A PES packet whose header is longer than the packet.
+	bad := pesPacket(0, nil)
+	bad[8] = 200
+	ts = append(ts, packets(testMetadataPID, bad)...)
+	// A PES packet with a truncated tag.
+	ts = append(ts, packets(testMetadataPID, pesPacket(1, []byte("ID3\x04\x00\x00\x00\x00\x00\x20TIT2")))...)
+	// A PES packet with a tag truncated within a frame header.
+	ts = append(ts, packets(testMetadataPID, pesPacket(2, []byte("ID3\x04\x00\x00\x00\x00\x00\x08TIT2\x00\x00\x00\x01")))...)
+	// Garbage between packets is skipped.
+	ts = append(ts, 0, 1, 2)
+	ts = append(ts, packets(testMetadataPID, pesPacket(180000, second))...)
+
+	r := NewReader(bytes.NewReader(ts))
+	var errs int
+	var tags []*Metadata
+	for {
+		m, err := r.Next()
+		if err != nil {
+			break
+		}
+		if m.Err != nil {
+			errs++
+			continue
+		}
+		tags = append(tags, m)
+	}
+	if errs != 3 {
+		t.Errorf("%d bad packets reported, want 3", errs)
+	}
+
+	all, err := ReadAll(bytes.NewReader(ts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || len(tags) != 3 {
+		t.Fatalf("read %d and %d tags, want 3", len(all), len(tags))
+	}
+	for i, want := range []struct {
+		pts       uint64
+		timestamp uint64
+	}{{0x123456789, 900000}, {0x123456789, 900000}, {180000, 1 << 32}} {
+		m := all[i]
+		if m.PID != testMetadataPID || !m.HasPTS || m.PTS != want.pts || m.Tag.Get("title") != "Segment" {
+			t.Errorf("%d: got PID %d, PTS %d, title %q", i, m.PID, m.PTS, m.Tag.Get("title"))
+		}
+		if ts, ok := m.Timestamp(); !ok || ts != want.timestamp {
+			t.Errorf("%d: timestamp %d, %v", i, ts, ok)
+		}
+	}
+	if d := all[2].Time(); d.Seconds() != 2 {
+		t.Errorf("time = %s", d)
+	}
+}
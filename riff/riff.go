@@ -0,0 +1,51 @@
+// Package riff reads and writes the ID3v2 tag embedded in an "id3 " or
+// "ID3 " chunk of a RIFF file, as written to WAV files by many audio
+// editors and broadcast tools.
+package riff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/lsongdev/id3-go/internal/iff"
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+// chunkIDs are the IDs of the chunk holding the tag, the first one being
+// used for new tags.
+var chunkIDs = []string{"id3 ", "ID3 "}
+
+// Read returns the ID3v2 tag of a RIFF file, or nil when it has none.
+func Read(r io.ReadSeeker) (*v2.ID3v2Tag, error) {
+	c, err := iff.Read(r, binary.LittleEndian, "RIFF")
+	if err != nil {
+		return nil, err
+	}
+	chunk, ok := c.Find(chunkIDs...)
+	if !ok {
+		return nil, nil
+	}
+	data, err := c.ReadChunk(r, chunk)
+	if err != nil {
+		return nil, err
+	}
+	return v2.Read(bytes.NewReader(data))
+}
+
+// Write copies the RIFF file from r to w with its ID3v2 tag replaced by tag,
+// or removed when tag is nil. The tag chunk is added at the end of the file
+// when there was none, and the RIFF size is updated.
+func Write(w io.Writer, r io.ReadSeeker, tag *v2.ID3v2Tag) error {
+	c, err := iff.Read(r, binary.LittleEndian, "RIFF")
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if tag != nil {
+		if data, err = tag.Bytes(); err != nil {
+			return err
+		}
+	}
+	return c.Replace(w, r, chunkIDs, data)
+}
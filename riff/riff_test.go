@@ -0,0 +1,58 @@
+package riff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	v2 "github.com/lsongdev/id3-go/v2"
+)
+
+func testWAV(trailer []byte) []byte {
+	var body bytes.Buffer
+	body.WriteString("WAVEfmt \x10\x00\x00\x00")
+	body.Write(make([]byte, 16))
+	body.WriteString("data\x03\x00\x00\x00\x01\x02\x03\x00")
+	file := append([]byte("RIFF\x00\x00\x00\x00"), body.Bytes()...)
+	binary.LittleEndian.PutUint32(file[4:], uint32(body.Len()))
+	return append(file, trailer...)
+}
+
+func TestWriteRead(t *testing.T) {
+	id3v1 := append([]byte("TAG"), make([]byte, 125)...)
+	file := testWAV(id3v1)
+	if tag, err := Read(bytes.NewReader(file)); err != nil || tag != nil {
+		t.Fatalf("untagged file: %v, %v", tag, err)
+	}
+
+	for _, title := range []string{"First", "Second title"} {
+		tag := v2.NewID3v2Tag(3)
+		tag.SetTextValues("TIT2", title)
+		var buf bytes.Buffer
+		if err := Write(&buf, bytes.NewReader(file), tag); err != nil {
+			t.Fatal(err)
+		}
+		file = buf.Bytes()
+		read, err := Read(bytes.NewReader(file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read == nil || read.Get("title") != title {
+			t.Errorf("read %v", read)
+		}
+		if size := binary.LittleEndian.Uint32(file[4:]); int(size) != len(file)-8-len(id3v1) {
+			t.Errorf("RIFF size %d for %d bytes", size, len(file))
+		}
+		if !bytes.HasSuffix(file, id3v1) {
+			t.Error("ID3v1 tag not kept at the end")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, bytes.NewReader(file), nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), testWAV(id3v1)) {
+		t.Errorf("removing the tag gave %q", buf.Bytes())
+	}
+}
@@ -0,0 +1,213 @@
+package v2
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// ChapterOffsetUnused is stored in place of a chapter byte offset when the
+// chapter is located by time only.
+const ChapterOffsetUnused uint32 = 0xFFFFFFFF
+
+// ChapterFrame holds a CHAP frame, a chapter located by start and end time
+// with embedded frames such as TIT2 for its title, WXXX for a link or APIC
+// for an image.
+//
+// Refer to http://id3.org/id3v2-chapters-1.0
+type ChapterFrame struct {
+	ElementID   string
+	StartTime   time.Duration
+	EndTime     time.Duration
+	StartOffset uint32
+	EndOffset   uint32
+	Frames      []*ID3v2Frame
+}
+
+func (c *ChapterFrame) String() string {
+	return c.ElementID
+}
+
+func (c *ChapterFrame) Keys() []string {
+	return []string{c.ElementID}
+}
+
+// Title returns the text of the chapter's embedded TIT2 frame.
+func (c *ChapterFrame) Title() string {
+	return subframeText(c.Frames, "TIT2")
+}
+
+func (c *ChapterFrame) Encode(version int) ([]byte, error) {
+	data := append(encodeString(EncodingISO8859_1, c.ElementID), 0)
+	data = binary.BigEndian.AppendUint32(data, uint32(c.StartTime/time.Millisecond))
+	data = binary.BigEndian.AppendUint32(data, uint32(c.EndTime/time.Millisecond))
+	data = binary.BigEndian.AppendUint32(data, c.StartOffset)
+	data = binary.BigEndian.AppendUint32(data, c.EndOffset)
+	return encodeSubframes(data, c.Frames, version)
+}
+
+// chapterFrameParser returns the CHAP constructor for the given version,
+// which is needed to read the embedded frames.
+func chapterFrameParser(version int) func([]byte) (ID3v2Framer, error) {
+	return func(data []byte) (ID3v2Framer, error) {
+		id, rest := splitString(EncodingISO8859_1, data)
+		if len(rest) < 16 {
+			return nil, fmt.Errorf("chapter frame too short")
+		}
+		frames, err := parseSubframes(rest[16:], version)
+		if err != nil {
+			return nil, err
+		}
+		return &ChapterFrame{
+			ElementID:   ISO8859_1ToUTF8(id),
+			StartTime:   time.Duration(binary.BigEndian.Uint32(rest[0:4])) * time.Millisecond,
+			EndTime:     time.Duration(binary.BigEndian.Uint32(rest[4:8])) * time.Millisecond,
+			StartOffset: binary.BigEndian.Uint32(rest[8:12]),
+			EndOffset:   binary.BigEndian.Uint32(rest[12:16]),
+			Frames:      frames,
+		}, nil
+	}
+}
+
+// TableOfContentsFrame holds a CTOC frame, listing the element IDs of the
+// chapters, or nested tables of contents, it contains. Only one table of
+// contents is the top-level one.
+//
+// Refer to http://id3.org/id3v2-chapters-1.0
+type TableOfContentsFrame struct {
+	ElementID       string
+	TopLevel        bool
+	Ordered         bool
+	ChildElementIDs []string
+	Frames          []*ID3v2Frame
+}
+
+func (t *TableOfContentsFrame) String() string {
+	return t.ElementID
+}
+
+func (t *TableOfContentsFrame) Keys() []string {
+	keys := []string{t.ElementID}
+	if t.TopLevel {
+		keys = append(keys, "top-level")
+	}
+	return keys
+}
+
+// Title returns the text of the table of contents' embedded TIT2 frame.
+func (t *TableOfContentsFrame) Title() string {
+	return subframeText(t.Frames, "TIT2")
+}
+
+func (t *TableOfContentsFrame) Encode(version int) ([]byte, error) {
+	if len(t.ChildElementIDs) > 255 {
+		return nil, fmt.Errorf("too many child elements: %d", len(t.ChildElementIDs))
+	}
+	data := append(encodeString(EncodingISO8859_1, t.ElementID), 0)
+	var flags byte
+	if t.TopLevel {
+		flags |= 0x02
+	}
+	if t.Ordered {
+		flags |= 0x01
+	}
+	data = append(data, flags, byte(len(t.ChildElementIDs)))
+	for _, id := range t.ChildElementIDs {
+		data = append(data, encodeString(EncodingISO8859_1, id)...)
+		data = append(data, 0)
+	}
+	return encodeSubframes(data, t.Frames, version)
+}
+
+// tableOfContentsFrameParser returns the CTOC constructor for the given
+// version, which is needed to read the embedded frames.
+func tableOfContentsFrameParser(version int) func([]byte) (ID3v2Framer, error) {
+	return func(data []byte) (ID3v2Framer, error) {
+		id, rest := splitString(EncodingISO8859_1, data)
+		if len(rest) < 2 {
+			return nil, fmt.Errorf("table of contents frame too short")
+		}
+		t := &TableOfContentsFrame{
+			ElementID: ISO8859_1ToUTF8(id),
+			TopLevel:  rest[0]&0x02 != 0,
+			Ordered:   rest[0]&0x01 != 0,
+		}
+		count := int(rest[1])
+		rest = rest[2:]
+		for i := 0; i < count; i++ {
+			if len(rest) == 0 {
+				return nil, fmt.Errorf("table of contents frame truncated")
+			}
+			var child []byte
+			child, rest = splitString(EncodingISO8859_1, rest)
+			t.ChildElementIDs = append(t.ChildElementIDs, ISO8859_1ToUTF8(child))
+		}
+		var err error
+		if t.Frames, err = parseSubframes(rest, version); err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+}
+
+// init registers CHAP and CTOC in V23FrameTypeMap and V24FrameTypeMap. It
+// cannot be done in the tables themselves, which would make an
+// initialization cycle since the constructors read sub-frames through them.
+func init() {
+	for _, version := range []int{3, 4} {
+		m := NewID3v2FrameParser(version).FrameTypeMap
+		m["CHAP"] = FrameType{id: "CHAP", description: "Chapter", constructor: chapterFrameParser(version)}
+		m["CTOC"] = FrameType{id: "CTOC", description: "Table of contents", constructor: tableOfContentsFrameParser(version)}
+	}
+}
+
+// parseSubframes reads the frames embedded in a CHAP or CTOC frame.
+func parseSubframes(data []byte, version int) ([]*ID3v2Frame, error) {
+	parser := NewID3v2FrameParser(version)
+	return parser.readFrames(bufio.NewReader(bytes.NewReader(data)), len(data))
+}
+
+// encodeSubframes appends the frames embedded in a CHAP or CTOC frame.
+func encodeSubframes(data []byte, frames []*ID3v2Frame, version int) ([]byte, error) {
+	for _, frame := range frames {
+		b, err := encodeFrame(frame, version)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return data, nil
+}
+
+func subframeText(frames []*ID3v2Frame, id string) string {
+	for _, frame := range frames {
+		if frame.Id == id && frame.Data != nil {
+			return frame.Data.String()
+		}
+	}
+	return ""
+}
+
+// Chapters returns the chapter frames of the tag in the order stored.
+func (tag *ID3v2Tag) Chapters() []*ChapterFrame {
+	var chapters []*ChapterFrame
+	for _, frame := range tag.Frames {
+		if c, ok := frame.Data.(*ChapterFrame); ok {
+			chapters = append(chapters, c)
+		}
+	}
+	return chapters
+}
+
+// TablesOfContents returns the table of contents frames of the tag.
+func (tag *ID3v2Tag) TablesOfContents() []*TableOfContentsFrame {
+	var tocs []*TableOfContentsFrame
+	for _, frame := range tag.Frames {
+		if t, ok := frame.Data.(*TableOfContentsFrame); ok {
+			tocs = append(tocs, t)
+		}
+	}
+	return tocs
+}
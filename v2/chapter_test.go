@@ -0,0 +1,86 @@
+package v2
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChapterFramesRoundTrip(t *testing.T) {
+	for _, version := range []int{3, 4} {
+		tag := NewID3v2Tag(version)
+		for i, title := range []string{"Intro", "Interview"} {
+			err := tag.AddFrame(&ID3v2Frame{Id: "CHAP", Data: &ChapterFrame{
+				ElementID:   []string{"ch0", "ch1"}[i],
+				StartTime:   time.Duration(i) * time.Minute,
+				EndTime:     time.Duration(i+1) * time.Minute,
+				StartOffset: ChapterOffsetUnused,
+				EndOffset:   ChapterOffsetUnused,
+				Frames:      []*ID3v2Frame{tag.newFrame("TIT2", NewTextFrame(title))},
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		err := tag.AddFrame(&ID3v2Frame{Id: "CTOC", Data: &TableOfContentsFrame{
+			ElementID:       "toc",
+			TopLevel:        true,
+			Ordered:         true,
+			ChildElementIDs: []string{"ch0", "ch1"},
+			Frames:          []*ID3v2Frame{tag.newFrame("TIT2", NewTextFrame("Episode"))},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tag.AddFrame(&ID3v2Frame{Id: "CHAP", Data: &ChapterFrame{ElementID: "ch1"}}); err == nil {
+			t.Error("chapter with a duplicate element ID accepted")
+		}
+		if err := tag.AddFrame(&ID3v2Frame{Id: "CTOC", Data: &TableOfContentsFrame{ElementID: "other", TopLevel: true}}); err == nil {
+			t.Error("second top-level table of contents accepted")
+		}
+
+		data, err := tag.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		read, err := Read(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		chapters := read.Chapters()
+		if len(chapters) != 2 {
+			t.Fatalf("v2.%d: got %d chapters", version, len(chapters))
+		}
+		c := chapters[1]
+		if c.ElementID != "ch1" || c.StartTime != time.Minute || c.EndTime != 2*time.Minute || c.Title() != "Interview" || c.StartOffset != ChapterOffsetUnused {
+			t.Errorf("v2.%d: chapter %+v, title %q", version, c, c.Title())
+		}
+		tocs := read.TablesOfContents()
+		if len(tocs) != 1 || !tocs[0].TopLevel || !tocs[0].Ordered || tocs[0].Title() != "Episode" ||
+			!reflect.DeepEqual(tocs[0].ChildElementIDs, []string{"ch0", "ch1"}) {
+			t.Errorf("v2.%d: table of contents %+v", version, tocs)
+		}
+	}
+}
+
+func TestChapterTruncatedSubframe(t *testing.T) {
+	times := strings.Repeat("\x00", 16)
+	for _, body := range []string{
+		"ch1\x00" + times + "TIT2\x00\x00\x00\x01",
+		"ch1\x00" + times + "TIT2\x7f\xff\xff\xff\x00\x00x",
+	} {
+		data := rawTag(t, 3, &ID3v2Frame{Id: "CHAP", Raw: []byte(body)})
+		tag, err := Read(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tag.Frames) != 1 {
+			t.Fatalf("got %d frames", len(tag.Frames))
+		}
+		if _, ok := tag.Frames[0].Data.(*DataFrame); !ok {
+			t.Errorf("%q decoded as %T", body, tag.Frames[0].Data)
+		}
+	}
+}
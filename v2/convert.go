@@ -0,0 +1,241 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// V22ToV23FrameIDs maps ID3v2.2 frame IDs to their ID3v2.3 equivalent. CRM
+// (encrypted meta frame) has none.
+var V22ToV23FrameIDs = map[string]string{
+	"BUF": "RBUF", "CNT": "PCNT", "COM": "COMM", "CRA": "AENC", "ETC": "ETCO",
+	"EQU": "EQUA", "GEO": "GEOB", "IPL": "IPLS", "LNK": "LINK", "MCI": "MCDI",
+	"MLL": "MLLT", "PIC": "APIC", "POP": "POPM", "REV": "RVRB", "RVA": "RVAD",
+	"SLT": "SYLT", "STC": "SYTC", "TAL": "TALB", "TBP": "TBPM", "TCM": "TCOM",
+	"TCO": "TCON", "TCR": "TCOP", "TDA": "TDAT", "TDY": "TDLY", "TEN": "TENC",
+	"TFT": "TFLT", "TIM": "TIME", "TKE": "TKEY", "TLA": "TLAN", "TLE": "TLEN",
+	"TMT": "TMED", "TOA": "TOPE", "TOF": "TOFN", "TOL": "TOLY", "TOR": "TORY",
+	"TOT": "TOAL", "TP1": "TPE1", "TP2": "TPE2", "TP3": "TPE3", "TP4": "TPE4",
+	"TPA": "TPOS", "TPB": "TPUB", "TRC": "TSRC", "TRD": "TRDA", "TRK": "TRCK",
+	"TSI": "TSIZ", "TSS": "TSSE", "TT1": "TIT1", "TT2": "TIT2", "TT3": "TIT3",
+	"TXT": "TEXT", "TXX": "TXXX", "TYE": "TYER", "UFI": "UFID", "ULT": "USLT",
+	"WAF": "WOAF", "WAR": "WOAR", "WAS": "WOAS", "WCM": "WCOM", "WCP": "WCOP",
+	"WPB": "WPUB", "WXX": "WXXX",
+}
+
+// V23ToV22FrameIDs is the inverse of V22ToV23FrameIDs.
+var V23ToV22FrameIDs = func() map[string]string {
+	m := make(map[string]string, len(V22ToV23FrameIDs))
+	for v22, v23 := range V22ToV23FrameIDs {
+		m[v23] = v22
+	}
+	return m
+}()
+
+// v24RemovedFrames lists the ID3v2.3 frames dropped by ID3v2.4. The date
+// frames are merged into TDRC and TDOR, IPLS becomes TIPL and RVAD becomes
+// RVA2; the others have no equivalent.
+var v24RemovedFrames = map[string]bool{
+	"EQUA": true, "IPLS": true, "RVAD": true, "TDAT": true, "TIME": true,
+	"TORY": true, "TRDA": true, "TSIZ": true, "TYER": true,
+}
+
+// dateFrameIDs lists the frames merged or split by ConvertTo through
+// RecordingTime and OriginalReleaseTime.
+var dateFrameIDs = map[string]bool{
+	"TYE": true, "TDA": true, "TIM": true, "TOR": true,
+	"TYER": true, "TDAT": true, "TIME": true, "TORY": true,
+	"TDRC": true, "TDOR": true,
+}
+
+// v22LayoutFrames lists the frames whose body differs between ID3v2.2 and
+// ID3v2.3: LNK holds a 3 character frame ID where LINK holds 4, and PIC a
+// 3 letter image format where APIC holds a MIME type.
+var v22LayoutFrames = map[string]bool{
+	"LNK": true, "LINK": true, "PIC": true, "APIC": true,
+}
+
+var originalDateFrameIDs = map[string]bool{
+	"TOR": true, "TORY": true, "TDOR": true,
+}
+
+// ConvertTo converts the tag to another major version (2, 3 or 4). Frame
+// IDs are mapped between versions, date frames are merged into TDRC and TDOR
+// when upgrading to ID3v2.4 and split into TYER, TDAT, TIME and TORY when
+// downgrading, IPLS is converted to and from TIPL/TMCL and RVAD to and from
+// RVA2. Frames without an equivalent in the target version are removed and
+// returned, as is every RVA2 frame but the first track or master one when
+// downgrading since RVAD holds a single adjustment. The recording dates
+// frame (TRDA, TRD in ID3v2.2) is kept alongside the split date frames.
+//
+// Frames kept undecoded as DataFrame, such as ETCO or AENC, are renamed with
+// their body unchanged, which suits the frames whose layout is the same in
+// every version. Those whose layout differs between ID3v2.2 and ID3v2.3,
+// such as LNK or an undecoded PIC, are removed and returned instead. Text
+// values are kept as they are, so "AC/DC" is not split into two artists
+// when upgrading to ID3v2.4. Frame flags are not read, so none are written
+// either.
+func (tag *ID3v2Tag) ConvertTo(version int) ([]*ID3v2Frame, error) {
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("unsupported ID3v2 version: %d", version)
+	}
+	from := tag.Header.Version
+	if from == version {
+		return nil, nil
+	}
+	recording, hasRecording := tag.RecordingTime()
+	original, hasOriginal := tag.OriginalReleaseTime()
+
+	var dropped []*ID3v2Frame
+	frames := tag.Frames
+	for from != version {
+		to := from + 1
+		if version < from {
+			to = from - 1
+		}
+		var converted []*ID3v2Frame
+		keptVolume := false
+		for _, frame := range frames {
+			if dateFrameIDs[frame.Id] {
+				converted = append(converted, frame)
+				continue
+			}
+			if to == 3 && frame.Id == "RVA2" {
+				// RVAD holds a single adjustment, which players apply per
+				// track.
+				if keptVolume || !isTrackVolume(frame) {
+					dropped = append(dropped, frame)
+					continue
+				}
+				keptVolume = true
+			}
+			f, err := convertFrame(frame, from, to)
+			if err != nil {
+				return nil, err
+			}
+			if f == nil {
+				dropped = append(dropped, frame)
+				continue
+			}
+			converted = append(converted, f)
+		}
+		frames = mergeInvolvedPeople(converted)
+		from = to
+	}
+
+	tag.Frames = nil
+	var recordingDates []*ID3v2Frame
+	for _, frame := range frames {
+		switch {
+		case frame.Id == "TRD" || frame.Id == "TRDA":
+			// SetRecordingTime removes it, so it is added back after.
+			recordingDates = append(recordingDates, frame)
+		case !dateFrameIDs[frame.Id]:
+			tag.Frames = append(tag.Frames, frame)
+		case originalDateFrameIDs[frame.Id] && !hasOriginal,
+			!originalDateFrameIDs[frame.Id] && !hasRecording:
+			dropped = append(dropped, frame)
+		}
+	}
+	tag.Header.Version = version
+	tag.Header.Revision = 0
+	if hasRecording {
+		tag.SetRecordingTime(recording)
+	}
+	if hasOriginal {
+		tag.SetOriginalReleaseTime(original)
+	}
+	tag.Frames = append(tag.Frames, recordingDates...)
+	return dropped, nil
+}
+
+// convertFrame converts a frame between adjacent versions, returning nil
+// when it has no equivalent.
+func convertFrame(frame *ID3v2Frame, from, to int) (*ID3v2Frame, error) {
+	id := frame.Id
+	data := frame.Data
+	if _, ok := data.(*DataFrame); ok && (from == 2 || to == 2) && v22LayoutFrames[id] {
+		return nil, nil
+	}
+	switch {
+	case from == 2:
+		id = V22ToV23FrameIDs[frame.Id]
+	case to == 2:
+		id = V23ToV22FrameIDs[frame.Id]
+	case to == 4:
+		switch frame.Id {
+		case "IPLS":
+			id = "TIPL"
+		case "RVAD":
+			id = "RVA2"
+			if v, ok := frame.Data.(*RelativeVolumeFrame); ok {
+				data = &RelativeVolumeFrame{Identification: "track", Channels: v.Channels}
+			}
+		default:
+			if v24RemovedFrames[id] {
+				id = ""
+			}
+		}
+	case to == 3:
+		switch frame.Id {
+		case "TIPL", "TMCL":
+			id = "IPLS"
+		case "RVA2":
+			id = "RVAD"
+			if v, ok := frame.Data.(*RelativeVolumeFrame); ok {
+				data = &RelativeVolumeFrame{Channels: v.Channels, RVAD: true}
+			}
+		}
+	}
+
+	t, ok := NewID3v2FrameParser(to).FrameTypeMap[id]
+	if !ok {
+		return nil, nil
+	}
+	if text, ok := data.(*TextFrame); ok {
+		data = convertTextFrame(id, text, to)
+	}
+	return &ID3v2Frame{
+		Id:          id,
+		Description: t.description,
+		Raw:         frame.Raw,
+		Data:        data,
+	}, nil
+}
+
+// convertTextFrame rewrites genres in the target version's preferred form.
+func convertTextFrame(id string, text *TextFrame, to int) *TextFrame {
+	if id == "TCO" || id == "TCON" {
+		genres := ParseGenres(strings.Join(text.Values, "\u0000"))
+		return genreTextFrame(genres, to)
+	}
+	return text
+}
+
+// isTrackVolume reports whether an RVA2 frame holds the track or master
+// adjustment.
+func isTrackVolume(frame *ID3v2Frame) bool {
+	v, ok := frame.Data.(*RelativeVolumeFrame)
+	return ok && (strings.EqualFold(v.Identification, "track") || strings.EqualFold(v.Identification, "master"))
+}
+
+// mergeInvolvedPeople joins several IPLS frames, which result from
+// converting both TIPL and TMCL, into one since only one is allowed.
+func mergeInvolvedPeople(frames []*ID3v2Frame) []*ID3v2Frame {
+	var merged *InvolvedPeopleFrame
+	result := frames[:0]
+	for _, frame := range frames {
+		p, ok := frame.Data.(*InvolvedPeopleFrame)
+		if frame.Id != "IPLS" || !ok {
+			result = append(result, frame)
+			continue
+		}
+		if merged == nil {
+			merged = &InvolvedPeopleFrame{}
+			frame.Data = merged
+			result = append(result, frame)
+		}
+		merged.Credits = append(merged.Credits, p.Credits...)
+	}
+	return result
+}
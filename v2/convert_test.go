@@ -0,0 +1,179 @@
+package v2
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func frameIDs(tag *ID3v2Tag) map[string]int {
+	ids := make(map[string]int)
+	for _, frame := range tag.Frames {
+		ids[frame.Id]++
+	}
+	return ids
+}
+
+func TestConvertUpgrade(t *testing.T) {
+	tag := NewID3v2Tag(2)
+	tag.SetTextValues("TT2", "Title")
+	tag.SetTextValues("TP1", "Alice/Bob")
+	tag.SetGenres([]Genre{{Index: 4, HasIndex: true, Name: "Disco", Refinement: "Eurodisco"}})
+	tag.SetRecordingTime(NewDate(time.Date(2001, 2, 3, 4, 5, 0, 0, time.UTC), PrecisionMinute))
+	tag.SetTextValues("TRD", "February 2001")
+	tag.SetTextValues("TSI", "1234")
+
+	dropped, err := tag.ConvertTo(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Header.Version != 4 {
+		t.Fatalf("version = %d", tag.Header.Version)
+	}
+	var droppedIDs []string
+	for _, frame := range dropped {
+		droppedIDs = append(droppedIDs, frame.Id)
+	}
+	if !reflect.DeepEqual(droppedIDs, []string{"TRDA", "TSIZ"}) {
+		t.Errorf("dropped %v, want [TRDA TSIZ]", droppedIDs)
+	}
+	if got := tag.Title(); got != "Title" {
+		t.Errorf("title = %q", got)
+	}
+	if got := tag.TextValues("TPE1"); !reflect.DeepEqual(got, []string{"Alice/Bob"}) {
+		t.Errorf("artists = %q", got)
+	}
+	if got := tag.TextValues("TCON"); !reflect.DeepEqual(got, []string{"(4)Eurodisco"}) {
+		t.Errorf("genre = %q", got)
+	}
+	if got := tag.TextValues("TDRC"); !reflect.DeepEqual(got, []string{"2001-02-03T04:05"}) {
+		t.Errorf("TDRC = %q", got)
+	}
+	ids := frameIDs(tag)
+	for _, id := range []string{"TYER", "TDAT", "TIME", "TRDA", "TYE", "TT2"} {
+		if ids[id] > 0 {
+			t.Errorf("%s left after conversion", id)
+		}
+	}
+	if _, err := tag.Bytes(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConvertDowngrade(t *testing.T) {
+	tag := NewID3v2Tag(4)
+	tag.SetTextValues("TIT2", "Title")
+	tag.SetTextValues("TPE1", "Alice", "Bob")
+	tag.SetTextValues("TDRC", "2001-02-03")
+	tag.SetTextValues("TDOR", "1999")
+	tag.SetTextValues("TMOO", "Calm")
+	tag.SetCredits([]Credit{{Role: "producer", Name: "Carol"}})
+	tag.SetMusicianCredits([]Credit{{Role: "bass", Name: "Dave"}})
+	for _, id := range []string{"album", "track", "master"} {
+		tag.AddFrame(tag.newFrame("RVA2", &RelativeVolumeFrame{
+			Identification: id,
+			Channels:       []VolumeChannel{{Type: ChannelMaster, Adjustment: -3}},
+		}))
+	}
+
+	dropped, err := tag.ConvertTo(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := frameIDs(tag)
+	if ids["RVAD"] != 1 || ids["IPLS"] != 1 || ids["TYER"] != 1 || ids["TDAT"] != 1 || ids["TORY"] != 1 {
+		t.Errorf("unexpected frames: %v", ids)
+	}
+	droppedIDs := make(map[string]int)
+	for _, frame := range dropped {
+		droppedIDs[frame.Id]++
+	}
+	if !reflect.DeepEqual(droppedIDs, map[string]int{"TMOO": 1, "RVA2": 2}) {
+		t.Errorf("dropped %v", droppedIDs)
+	}
+	if got := tag.Credits(); len(got) != 2 {
+		t.Errorf("credits = %v", got)
+	}
+
+	data, err := tag.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := read.TextValues("TPE1"); !reflect.DeepEqual(got, []string{"Alice", "Bob"}) {
+		t.Errorf("artists = %q", got)
+	}
+	if d, ok := read.RecordingTime(); !ok || d.String() != "2001-02-03" {
+		t.Errorf("recording time = %q, %v", d, ok)
+	}
+	v, ok := read.findFrame("RVAD").Data.(*RelativeVolumeFrame)
+	if !ok || len(v.Channels) == 0 || v.Channels[0].Adjustment > -2.9 || v.Channels[0].Adjustment < -3.1 {
+		t.Errorf("RVAD = %#v", read.findFrame("RVAD").Data)
+	}
+
+	if _, err := read.ConvertTo(2); err != nil {
+		t.Fatal(err)
+	}
+	if got := read.Get("title"); got != "Title" || read.findFrame("TT2") == nil {
+		t.Errorf("v2.2 title = %q", got)
+	}
+}
+
+func TestConvertKeepsRecordingDates(t *testing.T) {
+	tag := NewID3v2Tag(2)
+	tag.SetTextValues("TYE", "2001")
+	tag.SetTextValues("TRD", "February 2001")
+	dropped, err := tag.ConvertTo(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dropped) != 0 {
+		t.Errorf("dropped %d frames", len(dropped))
+	}
+	if got := tag.TextValues("TRDA"); !reflect.DeepEqual(got, []string{"February 2001"}) {
+		t.Errorf("TRDA = %q", got)
+	}
+}
+
+func TestConvertKeepsSlashValues(t *testing.T) {
+	tag := NewID3v2Tag(3)
+	tag.SetTextValues("TPE1", "AC/DC")
+	if _, err := tag.ConvertTo(4); err != nil {
+		t.Fatal(err)
+	}
+	if got := tag.TextValues("TPE1"); !reflect.DeepEqual(got, []string{"AC/DC"}) {
+		t.Errorf("artists = %q", got)
+	}
+}
+
+func TestConvertDropsUndecodedV22Layout(t *testing.T) {
+	data := rawTag(t, 2,
+		&ID3v2Frame{Id: "TT2", Raw: []byte("\x00Title")},
+		&ID3v2Frame{Id: "PIC", Raw: []byte("\x00PNG")},
+		&ID3v2Frame{Id: "LNK", Raw: []byte("TT2http://example.com/\x00")},
+		&ID3v2Frame{Id: "ETC", Raw: []byte{2}},
+	)
+	tag, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dropped, err := tag.ConvertTo(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var droppedIDs []string
+	for _, frame := range dropped {
+		droppedIDs = append(droppedIDs, frame.Id)
+	}
+	if !reflect.DeepEqual(droppedIDs, []string{"PIC", "LNK"}) {
+		t.Errorf("dropped %v, want [PIC LNK]", droppedIDs)
+	}
+	ids := frameIDs(tag)
+	if ids["ETCO"] != 1 || ids["TIT2"] != 1 {
+		t.Errorf("frames after conversion: %v", ids)
+	}
+}
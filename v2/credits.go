@@ -0,0 +1,120 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Credit is one entry of an involved people list: the role, or instrument
+// for musician credits, and the name of the person.
+type Credit struct {
+	Role string
+	Name string
+}
+
+// InvolvedPeopleFrame holds an involved people list (IPLS, IPL in ID3v2.2),
+// or in ID3v2.4 the involved people (TIPL) and musician credits (TMCL) text
+// frames which replace it. All store alternating roles and names.
+//
+// Refer to section 4.4 of http://id3.org/id3v2.3.0
+type InvolvedPeopleFrame struct {
+	Credits []Credit
+}
+
+func (p *InvolvedPeopleFrame) String() string {
+	credits := make([]string, len(p.Credits))
+	for i, c := range p.Credits {
+		credits[i] = c.Role + ": " + c.Name
+	}
+	return strings.Join(credits, ", ")
+}
+
+func (p *InvolvedPeopleFrame) Encode(version int) ([]byte, error) {
+	values := make([]string, 0, 2*len(p.Credits))
+	for _, c := range p.Credits {
+		values = append(values, c.Role, c.Name)
+	}
+	encoding := textEncoding(version, values...)
+	data := []byte{encoding}
+	for i, value := range values {
+		data = append(data, encodeString(encoding, value)...)
+		// Text frames in ID3v2.4 only separate values, IPLS terminates them.
+		if version < 4 || i < len(values)-1 {
+			data = append(data, stringTerminator(encoding)...)
+		}
+	}
+	return data, nil
+}
+
+func ParseInvolvedPeopleFrame(data []byte) (ID3v2Framer, error) {
+	values, err := parseStrings(data)
+	if err != nil {
+		return nil, err
+	}
+	p := &InvolvedPeopleFrame{}
+	for i := 0; i < len(values); i += 2 {
+		c := Credit{Role: values[i]}
+		if i+1 < len(values) {
+			c.Name = values[i+1]
+		}
+		p.Credits = append(p.Credits, c)
+	}
+	return p, nil
+}
+
+// Credits returns the involved people of the tag in the order stored,
+// followed in ID3v2.4 by the musician credits.
+func (tag *ID3v2Tag) Credits() []Credit {
+	var credits []Credit
+	for _, id := range []string{"IPL", "IPLS", "TIPL", "TMCL"} {
+		if frame := tag.findFrame(id); frame != nil {
+			if p, ok := frame.Data.(*InvolvedPeopleFrame); ok {
+				credits = append(credits, p.Credits...)
+			}
+		}
+	}
+	return credits
+}
+
+// MusicianCredits returns the musician credits of an ID3v2.4 tag, mapping
+// instruments to performers. Earlier versions do not tell them apart from
+// other credits.
+func (tag *ID3v2Tag) MusicianCredits() []Credit {
+	if frame := tag.findFrame("TMCL"); frame != nil {
+		if p, ok := frame.Data.(*InvolvedPeopleFrame); ok {
+			return p.Credits
+		}
+	}
+	return nil
+}
+
+// SetCredits replaces the involved people list (TIPL in ID3v2.4), leaving
+// musician credits untouched. No credits removes the frame.
+func (tag *ID3v2Tag) SetCredits(credits []Credit) {
+	id := "IPLS"
+	switch tag.Header.Version {
+	case 2:
+		id = "IPL"
+	case 4:
+		id = "TIPL"
+	}
+	tag.setCredits(id, credits)
+}
+
+// SetMusicianCredits replaces the musician credits list of an ID3v2.4 tag.
+// Earlier versions only have one list, set with SetCredits.
+func (tag *ID3v2Tag) SetMusicianCredits(credits []Credit) error {
+	if tag.Header.Version < 4 {
+		return fmt.Errorf("musician credits are not supported by ID3v2.%d", tag.Header.Version)
+	}
+	tag.setCredits("TMCL", credits)
+	return nil
+}
+
+func (tag *ID3v2Tag) setCredits(id string, credits []Credit) {
+	if len(credits) == 0 {
+		tag.RemoveFrames(id)
+		return
+	}
+	tag.replaceFrame(tag.newFrame(id, &InvolvedPeopleFrame{Credits: credits}))
+}
@@ -0,0 +1,77 @@
+package v2
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestCreditsRoundTrip(t *testing.T) {
+	credits := []Credit{{"producer", "Brian Eno"}, {"engineer", "Zoë"}}
+	musicians := []Credit{{"guitar", "Adrian Belew"}}
+	for _, version := range []int{2, 3, 4} {
+		tag := NewID3v2Tag(version)
+		tag.SetCredits(credits)
+		err := tag.SetMusicianCredits(musicians)
+		if version < 4 {
+			if err == nil {
+				t.Errorf("v2.%d: musician credits accepted", version)
+			}
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := tag.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		read, err := Read(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := credits
+		if version == 4 {
+			want = append(append([]Credit(nil), credits...), musicians...)
+			if got := read.MusicianCredits(); !reflect.DeepEqual(got, musicians) {
+				t.Errorf("v2.4: musician credits = %v", got)
+			}
+		}
+		if got := read.Credits(); !reflect.DeepEqual(got, want) {
+			t.Errorf("v2.%d: credits = %v, want %v", version, got, want)
+		}
+
+		read.SetCredits(nil)
+		if got := read.Credits(); version < 4 && got != nil {
+			t.Errorf("v2.%d: credits not removed: %v", version, got)
+		}
+	}
+}
+
+func TestInvolvedPeopleEncode(t *testing.T) {
+	p := &InvolvedPeopleFrame{Credits: []Credit{{"mix", "Ann"}}}
+	for _, tt := range []struct {
+		version int
+		want    []byte
+	}{
+		{3, []byte("\x00mix\x00Ann\x00")},
+		{4, []byte("\x03mix\x00Ann")},
+	} {
+		got, err := p.Encode(tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("v2.%d: got %q, want %q", tt.version, got, tt.want)
+		}
+	}
+
+	// A role without a name is kept.
+	parsed, err := ParseInvolvedPeopleFrame([]byte("\x00mix\x00Ann\x00solo\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Credit{{"mix", "Ann"}, {"solo", ""}}
+	if got := parsed.(*InvolvedPeopleFrame).Credits; !reflect.DeepEqual(got, want) {
+		t.Errorf("parsed %v, want %v", got, want)
+	}
+}
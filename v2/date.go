@@ -0,0 +1,256 @@
+package v2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DatePrecision tells which components of a Date are known.
+type DatePrecision int
+
+const (
+	PrecisionNone DatePrecision = iota
+	PrecisionYear
+	PrecisionMonth
+	PrecisionDay
+	PrecisionHour
+	PrecisionMinute
+	PrecisionSecond
+)
+
+var dateLayouts = []string{
+	PrecisionYear:   "2006",
+	PrecisionMonth:  "2006-01",
+	PrecisionDay:    "2006-01-02",
+	PrecisionHour:   "2006-01-02T15",
+	PrecisionMinute: "2006-01-02T15:04",
+	PrecisionSecond: "2006-01-02T15:04:05",
+}
+
+// Date is a timestamp stored in a tag together with its precision, since a
+// tag may only hold the year or the day of a recording. Components beyond
+// the precision are zero.
+type Date struct {
+	Time      time.Time
+	Precision DatePrecision
+}
+
+// NewDate creates a date truncated to the given precision.
+func NewDate(t time.Time, precision DatePrecision) Date {
+	d := Date{Precision: precision}
+	if precision > PrecisionNone {
+		d.Time, _ = time.Parse(dateLayouts[precision], t.Format(dateLayouts[precision]))
+	}
+	return d
+}
+
+func (d Date) IsZero() bool {
+	return d.Precision == PrecisionNone
+}
+
+// String formats the date as the ID3v2.4 timestamp subset of ISO-8601,
+// e.g. "2004-03" for a date of month precision.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Time.Format(dateLayouts[d.Precision])
+}
+
+// ParseDate parses an ID3v2.4 timestamp: "yyyy", "yyyy-MM", "yyyy-MM-dd",
+// "yyyy-MM-ddTHH", "yyyy-MM-ddTHH:mm" or "yyyy-MM-ddTHH:mm:ss". A space is
+// accepted in place of the "T".
+//
+// Refer to section 4 of http://id3.org/id3v2.4.0-structure
+func ParseDate(s string) (Date, bool) {
+	s = strings.Replace(strings.TrimSpace(s), " ", "T", 1)
+	for precision := PrecisionSecond; precision > PrecisionNone; precision-- {
+		if len(s) != len(dateLayouts[precision]) {
+			continue
+		}
+		t, err := time.Parse(dateLayouts[precision], s)
+		if err != nil {
+			return Date{}, false
+		}
+		return Date{Time: t, Precision: precision}, true
+	}
+	return Date{}, false
+}
+
+// dateFrames holds the IDs of the date frames used before ID3v2.4.
+type dateFrames struct {
+	year, date, time, recordingDates, originalYear string
+}
+
+func (tag *ID3v2Tag) dateFrames() dateFrames {
+	if tag.Header.Version == 2 {
+		return dateFrames{"TYE", "TDA", "TIM", "TRD", "TOR"}
+	}
+	return dateFrames{"TYER", "TDAT", "TIME", "TRDA", "TORY"}
+}
+
+func (tag *ID3v2Tag) textDate(id string) (Date, bool) {
+	frame := tag.findFrame(id)
+	if frame == nil {
+		return Date{}, false
+	}
+	return ParseDate(frame.Data.String())
+}
+
+// legacyDate assembles a date from the year (YYYY), date (DDMM) and time
+// (HHMM) frames of ID3v2.2 and ID3v2.3.
+func (tag *ID3v2Tag) legacyDate() (Date, bool) {
+	ids := tag.dateFrames()
+	frame := tag.findFrame(ids.year)
+	if frame == nil {
+		return Date{}, false
+	}
+	year, err := strconv.Atoi(strings.TrimSpace(frame.Data.String()))
+	if err != nil {
+		return Date{}, false
+	}
+	d := Date{Time: time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), Precision: PrecisionYear}
+
+	day, month, ok := parseDigitPair(tag.findFrame(ids.date))
+	if !ok || month < 1 || month > 12 || day < 1 || day > 31 {
+		return d, true
+	}
+	d.Time = time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	d.Precision = PrecisionDay
+
+	hour, minute, ok := parseDigitPair(tag.findFrame(ids.time))
+	if !ok || hour > 23 || minute > 59 {
+		return d, true
+	}
+	d.Time = time.Date(year, time.Month(month), day, hour, minute, 0, 0, time.UTC)
+	d.Precision = PrecisionMinute
+	return d, true
+}
+
+// parseDigitPair parses the four digit DDMM and HHMM frames.
+func parseDigitPair(frame *ID3v2Frame) (int, int, bool) {
+	if frame == nil {
+		return 0, 0, false
+	}
+	s := strings.TrimSpace(frame.Data.String())
+	if len(s) != 4 {
+		return 0, 0, false
+	}
+	a, err := strconv.Atoi(s[:2])
+	if err != nil {
+		return 0, 0, false
+	}
+	b, err := strconv.Atoi(s[2:])
+	if err != nil {
+		return 0, 0, false
+	}
+	return a, b, true
+}
+
+// RecordingTime returns the recording date from TDRC for ID3v2.4 and from
+// TYER, TDAT and TIME (TYE, TDA and TIM in ID3v2.2) for earlier versions.
+// The frames of the other versions are used as a fallback since taggers
+// often mix them.
+func (tag *ID3v2Tag) RecordingTime() (Date, bool) {
+	if tag.Header.Version >= 4 {
+		if d, ok := tag.textDate("TDRC"); ok {
+			return d, true
+		}
+		return tag.legacyDate()
+	}
+	if d, ok := tag.legacyDate(); ok {
+		return d, true
+	}
+	if d, ok := tag.textDate(tag.dateFrames().recordingDates); ok {
+		return d, true
+	}
+	return tag.textDate("TDRC")
+}
+
+// OriginalReleaseTime returns the original release date from TDOR for
+// ID3v2.4 and the original release year from TORY (TOR in ID3v2.2) for
+// earlier versions.
+func (tag *ID3v2Tag) OriginalReleaseTime() (Date, bool) {
+	if d, ok := tag.textDate("TDOR"); ok && tag.Header.Version >= 4 {
+		return d, true
+	}
+	if d, ok := tag.textDate(tag.dateFrames().originalYear); ok {
+		return NewDate(d.Time, PrecisionYear), true
+	}
+	return tag.textDate("TDOR")
+}
+
+// ReleaseTime returns the release date from TDRL, which only exists in
+// ID3v2.4.
+func (tag *ID3v2Tag) ReleaseTime() (Date, bool) {
+	return tag.textDate("TDRL")
+}
+
+// SetRecordingTime writes the recording date to TDRC for ID3v2.4 and to
+// TYER, TDAT and TIME (TYE, TDA and TIM in ID3v2.2) for earlier versions,
+// removing the frames of the other form. Before ID3v2.4, precision finer
+// than minutes is lost, and a date of PrecisionHour is written with minute
+// 00 since TIME has no hour only form, so it reads back as PrecisionMinute.
+// A zero date removes the recording date.
+func (tag *ID3v2Tag) SetRecordingTime(d Date) {
+	ids := tag.dateFrames()
+	tag.SetTextValues(ids.recordingDates)
+	if tag.Header.Version >= 4 || d.IsZero() {
+		tag.SetTextValues(ids.year)
+		tag.SetTextValues(ids.date)
+		tag.SetTextValues(ids.time)
+		if tag.Header.Version >= 4 && !d.IsZero() {
+			tag.SetTextValues("TDRC", d.String())
+		} else {
+			tag.SetTextValues("TDRC")
+		}
+		return
+	}
+
+	tag.SetTextValues("TDRC")
+	tag.SetTextValues(ids.year, fmt.Sprintf("%04d", d.Time.Year()))
+	if d.Precision >= PrecisionDay {
+		tag.SetTextValues(ids.date, d.Time.Format("0201"))
+	} else {
+		tag.SetTextValues(ids.date)
+	}
+	if d.Precision >= PrecisionHour {
+		tag.SetTextValues(ids.time, d.Time.Format("1504"))
+	} else {
+		tag.SetTextValues(ids.time)
+	}
+}
+
+// SetOriginalReleaseTime writes the original release date to TDOR for
+// ID3v2.4 and the year to TORY (TOR in ID3v2.2) for earlier versions. A
+// zero date removes it.
+func (tag *ID3v2Tag) SetOriginalReleaseTime(d Date) {
+	ids := tag.dateFrames()
+	tag.SetTextValues(ids.originalYear)
+	tag.SetTextValues("TDOR")
+	if d.IsZero() {
+		return
+	}
+	if tag.Header.Version >= 4 {
+		tag.SetTextValues("TDOR", d.String())
+	} else {
+		tag.SetTextValues(ids.originalYear, fmt.Sprintf("%04d", d.Time.Year()))
+	}
+}
+
+// SetReleaseTime writes the release date to TDRL. Earlier versions have no
+// release date frame so an error is returned for them. A zero date removes
+// it.
+func (tag *ID3v2Tag) SetReleaseTime(d Date) error {
+	if d.IsZero() {
+		tag.SetTextValues("TDRL")
+		return nil
+	}
+	if tag.Header.Version < 4 {
+		return fmt.Errorf("release time is not supported by ID3v2.%d", tag.Header.Version)
+	}
+	tag.SetTextValues("TDRL", d.String())
+	return nil
+}
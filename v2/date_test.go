@@ -0,0 +1,78 @@
+package v2
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		s         string
+		precision DatePrecision
+		ok        bool
+	}{
+		{"2004", PrecisionYear, true},
+		{"2004-03", PrecisionMonth, true},
+		{"2004-03-17", PrecisionDay, true},
+		{"2004-03-17T09", PrecisionHour, true},
+		{"2004-03-17 09:30", PrecisionMinute, true},
+		{"2004-03-17T09:30:15", PrecisionSecond, true},
+		{"2004-13", PrecisionNone, false},
+		{"March 2004", PrecisionNone, false},
+	}
+	for _, tt := range tests {
+		d, ok := ParseDate(tt.s)
+		if ok != tt.ok || d.Precision != tt.precision {
+			t.Errorf("ParseDate(%q) = %v, %v, want precision %v, %v", tt.s, d, ok, tt.precision, tt.ok)
+		}
+	}
+}
+
+func TestRecordingTimeRoundTrip(t *testing.T) {
+	at := time.Date(2004, 3, 17, 9, 30, 15, 0, time.UTC)
+	tests := []struct {
+		version   int
+		precision DatePrecision
+		want      string
+	}{
+		{4, PrecisionSecond, "2004-03-17T09:30:15"},
+		{4, PrecisionHour, "2004-03-17T09"},
+		{3, PrecisionYear, "2004"},
+		{3, PrecisionMonth, "2004"},
+		{3, PrecisionDay, "2004-03-17"},
+		{3, PrecisionHour, "2004-03-17T09:00"},
+		{3, PrecisionSecond, "2004-03-17T09:30"},
+		{2, PrecisionMinute, "2004-03-17T09:30"},
+	}
+	for _, tt := range tests {
+		tag := NewID3v2Tag(tt.version)
+		tag.SetRecordingTime(NewDate(at, tt.precision))
+		d, ok := tag.RecordingTime()
+		if !ok || d.String() != tt.want {
+			t.Errorf("v2.%d precision %d: got %q, %v, want %q", tt.version, tt.precision, d, ok, tt.want)
+		}
+	}
+}
+
+func TestReleaseTimes(t *testing.T) {
+	d := NewDate(time.Date(1999, 6, 1, 0, 0, 0, 0, time.UTC), PrecisionDay)
+	v3 := NewID3v2Tag(3)
+	v3.SetOriginalReleaseTime(d)
+	if got, ok := v3.OriginalReleaseTime(); !ok || got.String() != "1999" {
+		t.Errorf("v2.3 original release: got %q, %v", got, ok)
+	}
+	if err := v3.SetReleaseTime(d); err == nil {
+		t.Error("v2.3 release time: expected an error")
+	}
+	v4 := NewID3v2Tag(4)
+	v4.SetOriginalReleaseTime(d)
+	if err := v4.SetReleaseTime(d); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := v4.OriginalReleaseTime(); !ok || got.String() != "1999-06-01" {
+		t.Errorf("v2.4 original release: got %q, %v", got, ok)
+	}
+	if got, ok := v4.ReleaseTime(); !ok || got.String() != "1999-06-01" {
+		t.Errorf("v2.4 release: got %q, %v", got, ok)
+	}
+}
@@ -0,0 +1,79 @@
+package v2
+
+import "fmt"
+
+// Get returns the value of a field, such as "title" or "albumartist", by
+// resolving it to a frame ID through the V2xFrameMapping table of the tag's
+// version. An empty string is returned for unknown fields and missing
+// frames. For comments and lyrics the frame without a content description
+// is preferred.
+func (tag *ID3v2Tag) Get(field string) string {
+	id, ok := tag.frameMapping()[field]
+	if !ok {
+		return ""
+	}
+	frame := tag.fieldFrame(id)
+	if frame == nil || frame.Data == nil {
+		return ""
+	}
+	return frame.Data.String()
+}
+
+// Set writes the value of a field, resolving it to a frame ID through the
+// V2xFrameMapping table of the tag's version. An empty value removes the
+// field. An error is returned when the field is not available in the tag's
+// version.
+func (tag *ID3v2Tag) Set(field, value string) error {
+	id, ok := tag.frameMapping()[field]
+	if !ok {
+		return fmt.Errorf("unknown field for ID3v2.%d: %s", tag.Header.Version, field)
+	}
+	if !isUnsynchTextFrame(id) {
+		if value == "" {
+			tag.SetTextValues(id)
+		} else {
+			tag.SetTextValues(id, value)
+		}
+		return nil
+	}
+
+	tag.RemoveFrame(func(frame *ID3v2Frame) bool {
+		u, ok := frame.Data.(*UnsynchTextFrame)
+		return ok && frame.Id == id && u.Description == ""
+	})
+	if value != "" {
+		tag.Frames = append(tag.Frames, tag.newFrame(id, &UnsynchTextFrame{
+			Language: "XXX",
+			Text:     value,
+		}))
+	}
+	return nil
+}
+
+func isUnsynchTextFrame(id string) bool {
+	switch id {
+	case "COM", "COMM", "ULT", "USLT":
+		return true
+	}
+	return false
+}
+
+// fieldFrame returns the frame holding a field's value.
+func (tag *ID3v2Tag) fieldFrame(id string) *ID3v2Frame {
+	if !isUnsynchTextFrame(id) {
+		return tag.findFrame(id)
+	}
+	var first *ID3v2Frame
+	for _, frame := range tag.Frames {
+		if frame.Id != id {
+			continue
+		}
+		if u, ok := frame.Data.(*UnsynchTextFrame); ok && u.Description == "" {
+			return frame
+		}
+		if first == nil {
+			first = frame
+		}
+	}
+	return first
+}
@@ -0,0 +1,96 @@
+package v2
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGetSetFields(t *testing.T) {
+	for _, version := range []int{2, 3, 4} {
+		tag := NewID3v2Tag(version)
+		fields := map[string]string{
+			"title":       "Song",
+			"artist":      "Artist",
+			"album":       "Album",
+			"albumartist": "Various",
+			"composer":    "Composer",
+			"comment":     "Nice",
+		}
+		for field, value := range fields {
+			if err := tag.Set(field, value); err != nil {
+				t.Fatalf("v2.%d Set(%q): %s", version, field, err)
+			}
+		}
+		data, err := tag.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		read, err := Read(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for field, value := range fields {
+			if got := read.Get(field); got != value {
+				t.Errorf("v2.%d Get(%q) = %q, want %q", version, field, got, value)
+			}
+		}
+		if read.Title() != "Song" || read.Artist() != "Artist" || read.Album() != "Album" {
+			t.Errorf("v2.%d accessors: %q %q %q", version, read.Title(), read.Artist(), read.Album())
+		}
+		if err := read.Set("title", ""); err != nil || read.Get("title") != "" {
+			t.Errorf("v2.%d: title not removed", version)
+		}
+	}
+	if err := NewID3v2Tag(3).Set("nonsense", "x"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+// rawTag builds a tag from frames written from their Raw bytes.
+func rawTag(t *testing.T, version int, frames ...*ID3v2Frame) []byte {
+	t.Helper()
+	tag := NewID3v2Tag(version)
+	tag.Frames = frames
+	data, err := tag.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestMalformedFrameKeptRaw(t *testing.T) {
+	malformed := []*ID3v2Frame{
+		{Id: "SYLT", Raw: []byte{0, 'e', 'n'}},
+		{Id: "COMM", Raw: []byte{0, 'e'}},
+		{Id: "RVAD", Raw: []byte{3}},
+		{Id: "MLLT", Raw: []byte{0, 1}},
+		{Id: "POPM", Raw: []byte("no terminator")},
+	}
+	frames := append([]*ID3v2Frame{{Id: "TIT2", Raw: []byte("\x00Title")}}, malformed...)
+	data := rawTag(t, 3, frames...)
+	tag, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("malformed frames made the tag unreadable: %s", err)
+	}
+	if tag.Title() != "Title" {
+		t.Errorf("title = %q", tag.Title())
+	}
+	if len(tag.Frames) != len(frames) {
+		t.Fatalf("got %d frames, want %d", len(tag.Frames), len(frames))
+	}
+	for i, frame := range tag.Frames[1:] {
+		if _, ok := frame.Data.(*DataFrame); !ok {
+			t.Errorf("%s: got %T, want *DataFrame", frame.Id, frame.Data)
+		}
+		if !bytes.Equal(frame.Raw, malformed[i].Raw) {
+			t.Errorf("%s: raw bytes changed", frame.Id)
+		}
+	}
+	written, err := tag.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written, data) {
+		t.Error("malformed frames not written back unchanged")
+	}
+}
@@ -0,0 +1,164 @@
+package v2
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ID3v2FrameKeyer is implemented by frame data that may appear several times
+// in a tag. Two frames with the same ID conflict when they share any key,
+// e.g. comments are keyed by language and description.
+type ID3v2FrameKeyer interface {
+	Keys() []string
+}
+
+// multipleFrames lists the frames allowed several times in a tag. Those
+// whose data does not implement ID3v2FrameKeyer only conflict when their
+// content is identical.
+var multipleFrames = map[string]bool{
+	// ID3v2.2
+	"COM": true, "CRA": true, "GEO": true, "LNK": true, "PIC": true,
+	"POP": true, "SLT": true, "TXX": true, "UFI": true, "ULT": true,
+	"WAR": true, "WCM": true, "WXX": true,
+	// ID3v2.3 and ID3v2.4
+	"AENC": true, "APIC": true, "COMM": true, "COMR": true, "ENCR": true,
+	"EQU2": true, "GEOB": true, "GRID": true, "LINK": true, "POPM": true,
+	"PRIV": true, "RVA2": true, "SIGN": true, "SYLT": true, "TXXX": true,
+	"UFID": true, "USER": true, "USLT": true, "WCOM": true, "WOAR": true,
+	"WXXX": true, "CHAP": true, "CTOC": true,
+}
+
+// framesConflict reports whether two frames may not both appear in a tag.
+func framesConflict(a, b *ID3v2Frame) bool {
+	if a.Id != b.Id {
+		return false
+	}
+	if !multipleFrames[a.Id] {
+		return true
+	}
+	ka, ok := a.Data.(ID3v2FrameKeyer)
+	if !ok {
+		return bytes.Equal(frameContent(a), frameContent(b))
+	}
+	kb, ok := b.Data.(ID3v2FrameKeyer)
+	if !ok {
+		return false
+	}
+	for _, x := range ka.Keys() {
+		for _, y := range kb.Keys() {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// frameContent returns the body of a frame for comparison: its encoded data,
+// since frames built through the API have no Raw bytes, or Raw for frames
+// kept undecoded. Both frames being compared belong to the same tag, so any
+// version gives comparable bodies.
+func frameContent(frame *ID3v2Frame) []byte {
+	if encoder, ok := frame.Data.(ID3v2FrameEncoder); ok {
+		if data, err := encoder.Encode(4); err == nil {
+			return data
+		}
+	}
+	return frame.Raw
+}
+
+// checkFrame validates a frame ID against the tag's version and fills in a
+// missing description.
+func (tag *ID3v2Tag) checkFrame(frame *ID3v2Frame) error {
+	t, ok := NewID3v2FrameParser(tag.Header.Version).FrameTypeMap[frame.Id]
+	if !ok {
+		return fmt.Errorf("unknown frame type for ID3v2.%d: %s", tag.Header.Version, frame.Id)
+	}
+	if frame.Description == "" {
+		frame.Description = t.description
+	}
+	return nil
+}
+
+// newFrame creates a frame for the tag's version, filling in the
+// description from the version's frame type table.
+func (tag *ID3v2Tag) newFrame(id string, data ID3v2Framer) *ID3v2Frame {
+	frame := &ID3v2Frame{
+		Id:   id,
+		Data: data,
+	}
+	if t, ok := NewID3v2FrameParser(tag.Header.Version).FrameTypeMap[id]; ok {
+		frame.Description = t.description
+	}
+	return frame
+}
+
+// AddFrame appends a frame to the tag. An error is returned when the frame
+// is unknown to the tag's version or conflicts with an existing frame, such
+// as a second TIT2 or a second comment with the same language and
+// description.
+func (tag *ID3v2Tag) AddFrame(frame *ID3v2Frame) error {
+	if err := tag.checkFrame(frame); err != nil {
+		return err
+	}
+	for _, f := range tag.Frames {
+		if framesConflict(f, frame) {
+			return fmt.Errorf("frame conflicts with an existing %s frame", f.Id)
+		}
+	}
+	tag.Frames = append(tag.Frames, frame)
+	return nil
+}
+
+// SetFrame adds a frame to the tag, replacing the frames it conflicts with.
+// The frame takes the position of the first frame replaced.
+func (tag *ID3v2Tag) SetFrame(frame *ID3v2Frame) error {
+	if err := tag.checkFrame(frame); err != nil {
+		return err
+	}
+	tag.replaceFrame(frame)
+	return nil
+}
+
+// replaceFrame is SetFrame without validation.
+func (tag *ID3v2Tag) replaceFrame(frame *ID3v2Frame) {
+	frames := tag.Frames[:0]
+	replaced := false
+	for _, f := range tag.Frames {
+		if !framesConflict(f, frame) {
+			frames = append(frames, f)
+		} else if !replaced {
+			frames = append(frames, frame)
+			replaced = true
+		}
+	}
+	if !replaced {
+		frames = append(frames, frame)
+	}
+	tag.Frames = frames
+}
+
+// RemoveFrames removes every frame with the given id and returns the number
+// of frames removed.
+func (tag *ID3v2Tag) RemoveFrames(id string) int {
+	return tag.RemoveFrame(func(frame *ID3v2Frame) bool {
+		return frame.Id == id
+	})
+}
+
+// RemoveFrame removes every frame for which pred returns true and returns
+// the number of frames removed.
+func (tag *ID3v2Tag) RemoveFrame(pred func(*ID3v2Frame) bool) int {
+	frames := tag.Frames[:0]
+	for _, frame := range tag.Frames {
+		if !pred(frame) {
+			frames = append(frames, frame)
+		}
+	}
+	n := len(tag.Frames) - len(frames)
+	for i := len(frames); i < len(tag.Frames); i++ {
+		tag.Frames[i] = nil
+	}
+	tag.Frames = frames
+	return n
+}
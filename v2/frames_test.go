@@ -0,0 +1,69 @@
+package v2
+
+import "testing"
+
+func TestAddFrameUniqueness(t *testing.T) {
+	tag := NewID3v2Tag(3)
+	add := func(frame *ID3v2Frame) error { return tag.AddFrame(frame) }
+
+	if err := add(&ID3v2Frame{Id: "TIT2", Data: NewTextFrame("One")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := add(&ID3v2Frame{Id: "TIT2", Data: NewTextFrame("Two")}); err == nil {
+		t.Error("second TIT2 accepted")
+	}
+	if err := add(&ID3v2Frame{Id: "COMM", Data: &UnsynchTextFrame{Language: "eng", Text: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := add(&ID3v2Frame{Id: "COMM", Data: &UnsynchTextFrame{Language: "deu", Text: "b"}}); err != nil {
+		t.Errorf("comment in another language rejected: %s", err)
+	}
+	if err := add(&ID3v2Frame{Id: "COMM", Data: &UnsynchTextFrame{Language: "eng", Text: "c"}}); err == nil {
+		t.Error("comment with the same language and description accepted")
+	}
+	if err := add(&ID3v2Frame{Id: "ZZZZ", Data: NewTextFrame("x")}); err == nil {
+		t.Error("unknown frame accepted")
+	}
+}
+
+func TestAddFrameWithoutKeys(t *testing.T) {
+	tag := NewID3v2Tag(3)
+	first := &ID3v2Frame{Id: "WOAR", Data: &URLFrame{URL: "https://example.com/a"}}
+	second := &ID3v2Frame{Id: "WOAR", Data: &URLFrame{URL: "https://example.com/b"}}
+	if err := tag.AddFrame(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := tag.AddFrame(second); err != nil {
+		t.Errorf("second WOAR with another URL rejected: %s", err)
+	}
+	if err := tag.AddFrame(&ID3v2Frame{Id: "WOAR", Data: &URLFrame{URL: "https://example.com/a"}}); err == nil {
+		t.Error("duplicate WOAR accepted")
+	}
+	if err := tag.SetFrame(&ID3v2Frame{Id: "WOAR", Data: &URLFrame{URL: "https://example.com/c"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(tag.Frames) != 3 {
+		t.Errorf("SetFrame replaced a WOAR with another URL: %d frames", len(tag.Frames))
+	}
+
+	// Undecoded frames are compared on their raw bytes.
+	if err := tag.AddFrame(&ID3v2Frame{Id: "LINK", Data: &DataFrame{}, Raw: []byte("a")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tag.AddFrame(&ID3v2Frame{Id: "LINK", Data: &DataFrame{}, Raw: []byte("b")}); err != nil {
+		t.Errorf("second LINK rejected: %s", err)
+	}
+}
+
+func TestRemoveFrames(t *testing.T) {
+	tag := NewID3v2Tag(4)
+	tag.SetTextValues("TIT2", "Title")
+	tag.AddFrame(&ID3v2Frame{Id: "COMM", Data: &UnsynchTextFrame{Language: "eng", Text: "a"}})
+	tag.AddFrame(&ID3v2Frame{Id: "COMM", Data: &UnsynchTextFrame{Language: "deu", Text: "b"}})
+	if n := tag.RemoveFrames("COMM"); n != 2 {
+		t.Errorf("removed %d comments, want 2", n)
+	}
+	if len(tag.Frames) != 1 || tag.Frames[0].Id != "TIT2" {
+		t.Errorf("unexpected frames left: %v", tag.Frames)
+	}
+}
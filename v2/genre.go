@@ -0,0 +1,225 @@
+package v2
+
+import (
+	"strconv"
+	"strings"
+
+	v1 "github.com/lsongdev/id3-go/v1"
+)
+
+// Genre is a single entry of a TCON (TCO in ID3v2.2) frame.
+//
+// Index is the ID3v1 genre index when HasIndex is set, the zero value being
+// a free text genre given by Name. Refinement holds the text following a
+// "(NN)" reference in ID3v2.2 and ID3v2.3, e.g. "Eurodisco" in
+// "(4)Eurodisco". Remix and Cover are set for the RX and CR shorthands.
+type Genre struct {
+	Index      int
+	HasIndex   bool
+	Name       string
+	Refinement string
+	Remix      bool
+	Cover      bool
+}
+
+func (g Genre) String() string {
+	if g.Refinement != "" {
+		return g.Refinement
+	}
+	return g.Name
+}
+
+// ParseGenres parses the content of a TCON frame into its genres.
+//
+// ID3v2.2 and ID3v2.3 reference ID3v1 genres as "(NN)" and allow several
+// references followed by a refinement, e.g. "(4)(9)Eurodisco". A literal
+// "(" at the start of a refinement is escaped as "((". ID3v2.4 separates
+// multiple values with NUL and uses a bare "NN", "RX" or "CR". Both forms
+// are accepted regardless of the tag version since writers mix them freely.
+//
+// Refer to the following documentation:
+//
+//	http://id3.org/id3v2.3.0         TCON frame
+//	http://id3.org/id3v2.4.0-frames  TCON frame
+func ParseGenres(text string) []Genre {
+	var genres []Genre
+	for _, value := range strings.Split(text, "\u0000") {
+		genres = append(genres, parseGenreValue(strings.TrimSpace(value))...)
+	}
+	return genres
+}
+
+func parseGenreValue(value string) []Genre {
+	if value == "" {
+		return nil
+	}
+	var genres []Genre
+	rest := value
+	for strings.HasPrefix(rest, "(") && !strings.HasPrefix(rest, "((") {
+		end := strings.IndexByte(rest, ')')
+		if end < 0 {
+			break
+		}
+		g, ok := genreReference(rest[1:end])
+		if !ok {
+			break
+		}
+		rest = rest[end+1:]
+		g.Refinement, rest = splitRefinement(rest)
+		genres = append(genres, g)
+	}
+	if len(genres) > 0 {
+		return genres
+	}
+	if g, ok := genreReference(value); ok {
+		return []Genre{g}
+	}
+	return []Genre{{Name: unescapeRefinement(value)}}
+}
+
+// genreReference resolves the content of a "(..)" reference, or a bare
+// ID3v2.4 value, to a genre. Numbers outside the ID3v1 genre list are not
+// references, so that free text such as "1984" is kept as a name.
+func genreReference(token string) (Genre, bool) {
+	switch token {
+	case "RX":
+		return Genre{Name: "Remix", Remix: true}, true
+	case "CR":
+		return Genre{Name: "Cover", Cover: true}, true
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index >= len(v1.ID3v1Genres) {
+		return Genre{}, false
+	}
+	return Genre{Index: index, HasIndex: true, Name: v1.GetGenre(index)}, true
+}
+
+// startsReference reports whether s starts with a "(..)" genre reference.
+func startsReference(s string) bool {
+	end := strings.IndexByte(s, ')')
+	if !strings.HasPrefix(s, "(") || end < 0 {
+		return false
+	}
+	_, ok := genreReference(s[1:end])
+	return ok
+}
+
+// splitRefinement returns the text up to the next genre reference and the
+// remainder starting at that reference.
+func splitRefinement(s string) (string, string) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '(' {
+			continue
+		}
+		if strings.HasPrefix(s[i:], "((") {
+			i++
+			continue
+		}
+		end := strings.IndexByte(s[i:], ')')
+		if end < 0 {
+			break
+		}
+		if _, ok := genreReference(s[i+1 : i+end]); ok {
+			return unescapeRefinement(s[:i]), s[i:]
+		}
+	}
+	return unescapeRefinement(s), ""
+}
+
+func unescapeRefinement(s string) string {
+	return strings.ReplaceAll(s, "((", "(")
+}
+
+// escapeRefinement doubles every "(" that would otherwise be read as the
+// start of a reference or of an escape: a leading one, those starting a
+// reference and those next to another "(".
+func escapeRefinement(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '(' && (i == 0 || s[i-1] == '(' || strings.HasPrefix(s[i:], "((") || startsReference(s[i:])) {
+			b.WriteByte('(')
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// EncodeGenres is the inverse of ParseGenres. It returns the values to store
+// in a TCON frame using the preferred form of the given major version.
+//
+// ID3v2.4 gets one value per genre, the bare "NN", "RX" and "CR" being
+// written as "(NN)Refinement" when the genre has a refinement. ID3v2.2 and
+// ID3v2.3 get "(NN)Refinement" references concatenated into one value, and
+// each free text genre as a value of its own since it would otherwise be
+// read as the refinement of the preceding reference. Several values are
+// not portable before ID3v2.4, see SetGenres. A free text genre whose name
+// is "RX", "CR" or an ID3v1 genre index cannot be told apart from a
+// reference and is read back as one.
+func EncodeGenres(genres []Genre, version int) []string {
+	var values []string
+	var b strings.Builder
+	for _, g := range genres {
+		var ref string
+		switch {
+		case g.Remix:
+			ref = "RX"
+		case g.Cover:
+			ref = "CR"
+		case g.HasIndex:
+			ref = strconv.Itoa(g.Index)
+		default:
+			if b.Len() > 0 {
+				values = append(values, b.String())
+				b.Reset()
+			}
+			values = append(values, escapeRefinement(g.Name))
+			continue
+		}
+		if version >= 4 {
+			if g.Refinement == "" {
+				values = append(values, ref)
+			} else {
+				values = append(values, "("+ref+")"+escapeRefinement(g.Refinement))
+			}
+			continue
+		}
+		b.WriteString("(" + ref + ")" + escapeRefinement(g.Refinement))
+	}
+	if b.Len() > 0 {
+		values = append(values, b.String())
+	}
+	return values
+}
+
+// Genres returns every genre referenced by the tag's content type frame.
+func (tag *ID3v2Tag) Genres() []Genre {
+	values := tag.TextValues(tag.frameMapping()["genre"])
+	return ParseGenres(strings.Join(values, "\u0000"))
+}
+
+// SetGenres replaces the tag's content type frame using the preferred form
+// of the tag's version.
+//
+// ID3v2.2 and ID3v2.3 allow a single string, which holds any number of
+// references but only one free text genre. Further free text genres are
+// written after a NUL, as "/" would be read as part of the name. This is
+// not portable: many readers stop at the first NUL and only see the
+// genres before it.
+func (tag *ID3v2Tag) SetGenres(genres []Genre) {
+	id := tag.frameMapping()["genre"]
+	if len(genres) == 0 {
+		tag.SetTextValues(id)
+		return
+	}
+	tag.replaceFrame(tag.newFrame(id, genreTextFrame(genres, tag.Header.Version)))
+}
+
+// genreTextFrame creates the content type frame for the given version,
+// joining the values with NUL before ID3v2.4 as described by SetGenres.
+func genreTextFrame(genres []Genre, version int) *TextFrame {
+	values := EncodeGenres(genres, version)
+	if version < 4 && len(values) > 1 {
+		values = []string{strings.Join(values, "\u0000")}
+	}
+	return NewTextFrame(values...)
+}
@@ -0,0 +1,86 @@
+package v2
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGenres(t *testing.T) {
+	tests := []struct {
+		text string
+		want []Genre
+	}{
+		{"(17)", []Genre{{Index: 17, HasIndex: true, Name: "Rock"}}},
+		{"17", []Genre{{Index: 17, HasIndex: true, Name: "Rock"}}},
+		{"(4)(9)Eurodisco", []Genre{{Index: 4, HasIndex: true, Name: "Disco"}, {Index: 9, HasIndex: true, Name: "Metal", Refinement: "Eurodisco"}}},
+		{"(4)Eurodisco(9)", []Genre{{Index: 4, HasIndex: true, Name: "Disco", Refinement: "Eurodisco"}, {Index: 9, HasIndex: true, Name: "Metal"}}},
+		{"RX\x00CR", []Genre{{Name: "Remix", Remix: true}, {Name: "Cover", Cover: true}}},
+		{"((Parens)", []Genre{{Name: "(Parens)"}}},
+		{"Shoegaze\x0017", []Genre{{Name: "Shoegaze"}, {Index: 17, HasIndex: true, Name: "Rock"}}},
+		{"1984", []Genre{{Name: "1984"}}},
+	}
+	for _, tt := range tests {
+		if got := ParseGenres(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseGenres(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeGenresRoundTrip(t *testing.T) {
+	tests := [][]Genre{
+		{{Index: 17, HasIndex: true, Name: "Rock"}},
+		{{Index: 4, HasIndex: true, Name: "Disco", Refinement: "Eurodisco"}},
+		{{Index: 4, HasIndex: true, Name: "Disco"}, {Index: 9, HasIndex: true, Name: "Metal", Refinement: "Eurodisco"}},
+		{{Name: "Remix", Remix: true, Refinement: "Club"}},
+		{{Name: "Shoegaze"}, {Name: "Dream Pop"}},
+		{{Index: 17, HasIndex: true, Name: "Rock"}, {Name: "Krautrock"}, {Index: 9, HasIndex: true, Name: "Metal"}},
+		{{Name: "1984"}},
+		{{Name: "(Parens)"}, {Name: "a((b"}},
+		{{Index: 4, HasIndex: true, Name: "Disco", Refinement: "Italo (2) and (4) more"}},
+	}
+	for _, genres := range tests {
+		for _, version := range []int{3, 4} {
+			values := EncodeGenres(genres, version)
+			got := ParseGenres(strings.Join(values, "\x00"))
+			if !reflect.DeepEqual(got, genres) {
+				t.Errorf("v2.%d: %+v encoded as %q, read back as %+v", version, genres, values, got)
+			}
+		}
+	}
+}
+
+func TestSetGenres(t *testing.T) {
+	genres := []Genre{{Index: 17, HasIndex: true, Name: "Rock"}, {Name: "Ĳsselmeer"}, {Name: "Dub"}}
+	for _, version := range []int{2, 3, 4} {
+		tag := NewID3v2Tag(version)
+		tag.SetGenres(genres)
+		data, err := tag.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		read, err := Read(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := read.Genres(); !reflect.DeepEqual(got, genres) {
+			t.Errorf("v2.%d: got %+v, want %+v", version, got, genres)
+		}
+	}
+}
+
+func TestGenreFromName(t *testing.T) {
+	// The zero value of a genre is free text, not the ID3v1 genre 0.
+	for _, version := range []int{3, 4} {
+		tag := NewID3v2Tag(version)
+		tag.SetGenres([]Genre{{Name: "Synthwave"}, {Name: "Blues"}})
+		if got := tag.TextValues("TCON"); len(got) == 0 || !strings.HasPrefix(got[0], "Synthwave") {
+			t.Errorf("v2.%d: written as %q", version, got)
+		}
+		want := []Genre{{Name: "Synthwave"}, {Name: "Blues"}}
+		if got := tag.Genres(); !reflect.DeepEqual(got, want) {
+			t.Errorf("v2.%d: read back as %+v", version, got)
+		}
+	}
+}
@@ -40,8 +40,6 @@ func (h *ID3v2Tag) Version() string {
 	return fmt.Sprintf("2.%d.%d", h.Header.Version, h.Header.Revision)
 }
 
-var skipBuffer []byte = make([]byte, 1024*4)
-
 // A parsed ID3v2 header as defined in Section 3 of
 // http://id3.org/id3v2.4.0-structure
 type ID3v2Header struct {
@@ -73,10 +71,10 @@ func ParseID3v2Header(reader *bufio.Reader) (*ID3v2Header, error) {
 	h := new(ID3v2Header)
 	h.Version = int(data[3])
 	h.Revision = int(data[4])
-	h.Unsynchronization = data[5]&1<<7 != 0
-	h.Extended = data[5]&1<<6 != 0
-	h.Experimental = data[5]&1<<5 != 0
-	h.Footer = data[5]&1<<4 != 0
+	h.Unsynchronization = data[5]&(1<<7) != 0
+	h.Extended = data[5]&(1<<6) != 0
+	h.Experimental = data[5]&(1<<5) != 0
+	h.Footer = data[5]&(1<<4) != 0
 	h.Size = parseSize(data[6:])
 	return h, nil
 }
@@ -94,22 +92,9 @@ func Read(data io.Reader) (tag *ID3v2Tag, err error) {
 	}
 	frameReader := NewID3v2FrameParser(h.Version)
 	rd := io.LimitReader(bufReader, int64(h.Size))
-	reader := bufio.NewReader(rd)
-	for frameReader.hasFrame(reader) {
-		frame, err := frameReader.readFrame(reader)
-		if err != nil {
-			return nil, err
-		}
-		t, ok := frameReader.FrameTypeMap[frame.Id]
-		if !ok {
-			return nil, fmt.Errorf("unknown frame type: %s", frame.Id)
-		}
-		frame.Description = t.description
-		frame.Data, err = t.constructor(frame.Raw)
-		if err != nil {
-			return nil, err
-		}
-		tag.Frames = append(tag.Frames, frame)
+	tag.Frames, err = frameReader.readFrames(bufio.NewReader(rd), int(h.Size))
+	if err != nil {
+		return nil, err
 	}
 	return
 }
@@ -144,14 +129,14 @@ func NewID3v2FrameParser(version int) *ID3v2FrameParser {
 		p.IdLen = 4
 		p.SizeLen = 4
 		p.SizeParser = ParseID3v24FrameSize
-		p.FrameTypeMap = V23FrameTypeMap
+		p.FrameTypeMap = V24FrameTypeMap
 	}
 	return p
 }
 
 // Peeks at the buffer to see if there is a valid frame.
 func (p *ID3v2FrameParser) hasFrame(reader *bufio.Reader) bool {
-	data, err := reader.Peek(p.SizeLen)
+	data, err := reader.Peek(p.IdLen)
 	if err != nil {
 		return false
 	}
@@ -164,7 +149,39 @@ func (p *ID3v2FrameParser) hasFrame(reader *bufio.Reader) bool {
 	return true
 }
 
-func (parser *ID3v2FrameParser) readFrame(reader *bufio.Reader) (frame *ID3v2Frame, err error) {
+// readFrames reads and decodes frames until the reader holds no more valid
+// frames, which happens at the padding or the end of the data. The reader
+// holds at most size bytes.
+func (parser *ID3v2FrameParser) readFrames(reader *bufio.Reader, size int) ([]*ID3v2Frame, error) {
+	var frames []*ID3v2Frame
+	for parser.hasFrame(reader) {
+		frame, err := parser.readFrame(reader, size)
+		if err != nil {
+			return nil, err
+		}
+		size -= parser.HeaderLen + len(frame.Raw)
+		t, ok := parser.FrameTypeMap[frame.Id]
+		if !ok {
+			// Unknown frames, such as those of later or experimental
+			// versions, are kept undecoded and written back as read.
+			frame.Data, _ = ParseDataFrame(frame.Raw)
+			frames = append(frames, frame)
+			continue
+		}
+		frame.Description = t.description
+		// A malformed frame is kept undecoded, and written back from its
+		// Raw bytes, rather than making the whole tag unreadable.
+		if frame.Data, err = t.constructor(frame.Raw); err != nil {
+			frame.Data, _ = ParseDataFrame(frame.Raw)
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// readFrame reads a frame from a reader holding at most remaining bytes,
+// which bounds the size the frame header may claim.
+func (parser *ID3v2FrameParser) readFrame(reader *bufio.Reader, remaining int) (frame *ID3v2Frame, err error) {
 	id, err := readBytes(reader, parser.IdLen)
 	if err != nil {
 		return nil, err
@@ -174,7 +191,13 @@ func (parser *ID3v2FrameParser) readFrame(reader *bufio.Reader) (frame *ID3v2Fra
 		return nil, err
 	}
 	size := parser.SizeParser(sizeBytes)
-	skipBytes(reader, 2)
+	// ID3v2.2 frame headers have no flags.
+	if _, err = readBytes(reader, parser.HeaderLen-parser.IdLen-parser.SizeLen); err != nil {
+		return nil, err
+	}
+	if size < 0 || size > remaining-parser.HeaderLen {
+		return nil, fmt.Errorf("frame %s size %d exceeds the %d bytes left", id, size, remaining-parser.HeaderLen)
+	}
 	data, err := readBytes(reader, size)
 	if err != nil {
 		return nil, err
@@ -202,6 +225,16 @@ func parseSize(data []byte) int32 {
 	return size
 }
 
+// encodeSize is the inverse of parseSize, producing n bytes of 7 bits each.
+func encodeSize(size int32, n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		shift := uint32(n-i-1) * 7
+		data[i] = byte(size>>shift) & 0x7f
+	}
+	return data
+}
+
 func ISO8859_1ToUTF8(data []byte) string {
 	p := make([]rune, len(data))
 	for i, b := range data {
@@ -210,62 +243,174 @@ func ISO8859_1ToUTF8(data []byte) string {
 	return string(p)
 }
 
-func toUTF16(data []byte) []uint16 {
-	if len(data) < 2 {
-		panic("Sequence is too short too contain a UTF-16 BOM")
-	}
-	if len(data)%2 > 0 {
-		// TODO: if this is UTF-16 BE then this is likely encoded wrong
-		data = append(data, 0)
-	}
+// Text encodings as stored in the first byte of text frames.
+//
+// Refer to section 4 of http://id3.org/id3v2.4.0-structure
+const (
+	EncodingISO8859_1 byte = 0x00
+	EncodingUTF16     byte = 0x01
+	EncodingUTF16BE   byte = 0x02
+	EncodingUTF8      byte = 0x03
+)
 
-	var shift0, shift1 uint
-	if data[0] == 0xFF && data[1] == 0xFE {
-		// UTF-16 LE
-		shift0 = 0
-		shift1 = 8
-	} else if data[0] == 0xFE && data[1] == 0xFF {
-		// UTF-16 BE
-		shift0 = 8
-		shift1 = 0
-		panic("UTF-16 BE found!")
-	} else {
-		panic(fmt.Sprintf("Unrecognized UTF-16 BOM: 0x%02X%02X", data[0], data[1]))
+// toUTF16 converts UTF-16 data to code units. A leading BOM selects the byte
+// order, otherwise bigEndian is used.
+func toUTF16(data []byte, bigEndian bool) []uint16 {
+	if len(data) >= 2 {
+		if data[0] == 0xFF && data[1] == 0xFE {
+			bigEndian = false
+			data = data[2:]
+		} else if data[0] == 0xFE && data[1] == 0xFF {
+			bigEndian = true
+			data = data[2:]
+		}
 	}
 
 	s := make([]uint16, 0, len(data)/2)
-	for i := 2; i < len(data); i += 2 {
-		s = append(s, uint16(data[i])<<shift0|uint16(data[i+1])<<shift1)
+	for i := 0; i+1 < len(data); i += 2 {
+		if bigEndian {
+			s = append(s, uint16(data[i])<<8|uint16(data[i+1]))
+		} else {
+			s = append(s, uint16(data[i])|uint16(data[i+1])<<8)
+		}
 	}
 	return s
 }
 
+// decodeString decodes data stored with the given text encoding.
+func decodeString(encoding byte, data []byte) (s string, err error) {
+	switch encoding {
+	case EncodingISO8859_1:
+		s = ISO8859_1ToUTF8(data)
+	case EncodingUTF16:
+		s = string(utf16.Decode(toUTF16(data, false)))
+	case EncodingUTF16BE:
+		s = string(utf16.Decode(toUTF16(data, true)))
+	case EncodingUTF8:
+		s = string(data)
+	default:
+		err = fmt.Errorf("Unsupported text encoding: 0x" + fmt.Sprintf("%02X", encoding))
+		return
+	}
+	return strings.TrimRight(s, "\u0000"), nil
+}
+
+// splitString splits data at the first string terminator of the given
+// encoding, returning the string bytes and the bytes following the
+// terminator. The terminator is two aligned zero bytes for UTF-16.
+func splitString(encoding byte, data []byte) ([]byte, []byte) {
+	if encoding == EncodingUTF16 || encoding == EncodingUTF16BE {
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				return data[:i], data[i+2:]
+			}
+		}
+		return data, nil
+	}
+	for i, b := range data {
+		if b == 0 {
+			return data[:i], data[i+1:]
+		}
+	}
+	return data, nil
+}
+
 // Parses a string from frame data. The first byte represents the encoding:
 //
-//	0x01  ISO-8859-1
-//	0x02  UTF-16 w/ BOM
-//	0x03  UTF-16BE w/o BOM
-//	0x04  UTF-8
+//	0x00  ISO-8859-1
+//	0x01  UTF-16 w/ BOM
+//	0x02  UTF-16BE w/o BOM
+//	0x03  UTF-8
 //
 // Refer to section 4 of http://id3.org/id3v2.4.0-structure
 func parseString(data []byte) (s string, err error) {
-	switch data[0] {
-	case 0x00: // ISO-8859-1 text.
-		s = ISO8859_1ToUTF8(data[1:])
-	case 0x01: // UTF-16 with BOM.
-		s = string(utf16.Decode(toUTF16(data[1:])))
-	case 0x02: // UTF-16BE without BOM.
-		err = fmt.Errorf("unsupported text encoding UTF-16BE")
-		return
-	case 0x03: // UTF-8 text.
-		s = string(data[1:])
+	if len(data) == 0 {
+		return "", nil
+	}
+	return decodeString(data[0], data[1:])
+}
+
+// parseStrings parses the NUL separated list of strings held by ID3v2.4 text
+// frames. Trailing empty strings are dropped.
+func parseStrings(data []byte) ([]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	encoding, rest := data[0], data[1:]
+	var values []string
+	for len(rest) > 0 {
+		var b []byte
+		b, rest = splitString(encoding, rest)
+		s, err := decodeString(encoding, b)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, s)
+	}
+	for len(values) > 0 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+	return values, nil
+}
+
+// textEncoding picks the encoding used to write strings for the given major
+// version: UTF-8 for ID3v2.4, otherwise ISO-8859-1 when every character fits
+// and UTF-16 with BOM when not.
+func textEncoding(version int, values ...string) byte {
+	if version >= 4 {
+		return EncodingUTF8
+	}
+	for _, s := range values {
+		for _, r := range s {
+			if r > 0xFF {
+				return EncodingUTF16
+			}
+		}
+	}
+	return EncodingISO8859_1
+}
+
+// encodeString encodes s with the given text encoding, without terminator.
+func encodeString(encoding byte, s string) []byte {
+	switch encoding {
+	case EncodingUTF16, EncodingUTF16BE:
+		units := utf16.Encode([]rune(s))
+		b := make([]byte, 0, 2+len(units)*2)
+		if encoding == EncodingUTF16 {
+			// Every NUL separated string starts with its own BOM.
+			b = append(b, 0xFF, 0xFE)
+			for i, u := range units {
+				b = append(b, byte(u), byte(u>>8))
+				if u == 0 && i+1 < len(units) {
+					b = append(b, 0xFF, 0xFE)
+				}
+			}
+			return b
+		}
+		for _, u := range units {
+			b = append(b, byte(u>>8), byte(u))
+		}
+		return b
+	case EncodingUTF8:
+		return []byte(s)
 	default:
-		// No encoding, assume ISO-8859-1 text.
-		// s = ISO8859_1ToUTF8(data)
-		err = fmt.Errorf("Unsupported text encoding: 0x" + fmt.Sprintf("%02X", data[0]))
-		return
+		b := make([]byte, 0, len(s))
+		for _, r := range s {
+			if r > 0xFF {
+				r = '?'
+			}
+			b = append(b, byte(r))
+		}
+		return b
 	}
-	return strings.TrimRight(s, "\u0000"), nil
+}
+
+// stringTerminator returns the terminator of the given text encoding.
+func stringTerminator(encoding byte) []byte {
+	if encoding == EncodingUTF16 || encoding == EncodingUTF16BE {
+		return []byte{0, 0}
+	}
+	return []byte{0}
 }
 
 // ID3v2.2 and ID3v2.3 use "(NN)" where as ID3v2.4 simply uses "NN" when
@@ -326,72 +471,148 @@ func readBytes(reader *bufio.Reader, c int) ([]byte, error) {
 	return b, nil
 }
 
-func skipBytes(reader *bufio.Reader, c int) {
-	pos := 0
-	for pos < c {
-		end := c - pos
-		if end > len(skipBuffer) {
-			end = len(skipBuffer)
-		}
+func (tag *ID3v2Tag) Title() string {
+	return tag.Get("title")
+}
 
-		i, err := reader.Read(skipBuffer[0:end])
-		pos += i
-		if err != nil {
-			panic(err)
-		}
+func (tag *ID3v2Tag) Album() string {
+	return tag.Get("album")
+}
+
+func (tag *ID3v2Tag) Artist() string {
+	return tag.Get("artist")
+}
+
+func (tag *ID3v2Tag) Genre() string {
+	return tag.Get("genre")
+}
+
+// Year returns the year of the recording time, see RecordingTime.
+func (tag *ID3v2Tag) Year() string {
+	d, ok := tag.RecordingTime()
+	if !ok {
+		return ""
 	}
+	return strconv.Itoa(d.Time.Year())
 }
 
-func (tag *ID3v2Tag) Title() string {
+// Cover returns the front cover image, or the first attached picture when
+// there is no front cover.
+func (tag *ID3v2Tag) Cover() []byte {
+	var first *ImageFrame
 	for _, frame := range tag.Frames {
-		if frame.Id == "TIT2" {
-			return frame.Data.String()
+		image, ok := frame.Data.(*ImageFrame)
+		if !ok {
+			continue
+		}
+		if image.PictureType == PictureTypeFrontCover {
+			return image.Data
 		}
+		if first == nil {
+			first = image
+		}
+	}
+	if first == nil {
+		return nil
 	}
-	return ""
+	return first.Data
 }
 
-func (tag *ID3v2Tag) Album() string {
-	for _, frame := range tag.Frames {
-		if frame.Id == "TALB" {
-			return frame.Data.String()
-		}
+// frameMapping returns the field name to frame ID table for the tag's version.
+func (tag *ID3v2Tag) frameMapping() map[string]string {
+	switch tag.Header.Version {
+	case 2:
+		return V22FrameMapping
+	case 4:
+		return V24FrameMapping
+	default:
+		return V23FrameMapping
 	}
-	return ""
 }
 
-func (tag *ID3v2Tag) Artist() string {
+// findFrame returns the first frame with the given id, or nil.
+func (tag *ID3v2Tag) findFrame(id string) *ID3v2Frame {
 	for _, frame := range tag.Frames {
-		if frame.Id == "TPE1" {
-			return frame.Data.String()
+		if frame.Id == id {
+			return frame
 		}
 	}
-	return ""
+	return nil
 }
 
-func (tag *ID3v2Tag) Genre() string {
-	for _, frame := range tag.Frames {
-		if frame.Id == "TCON" {
-			return frame.Data.String()
-		}
+// slashSeparatedFrames lists the ID3v2.2 and ID3v2.3 text frames in which
+// "/" separates several values.
+var slashSeparatedFrames = map[string]bool{
+	"TCM": true, "TOA": true, "TOL": true, "TP1": true, "TXT": true,
+	"TCOM": true, "TEXT": true, "TOLY": true, "TOPE": true, "TPE1": true,
+}
+
+// TextValues returns the values of the text frame with the given id. For
+// ID3v2.2 and ID3v2.3 the artist, composer and lyricist frames are split on
+// "/" since those versions have no other way to store several values.
+func (tag *ID3v2Tag) TextValues(id string) []string {
+	frame := tag.findFrame(id)
+	if frame == nil {
+		return nil
+	}
+	text, ok := frame.Data.(*TextFrame)
+	if !ok {
+		return nil
 	}
-	return ""
+	if tag.Header.Version >= 4 || !slashSeparatedFrames[id] {
+		return text.Values
+	}
+	return splitSlashValues(text.Values)
 }
 
-func (tag *ID3v2Tag) Year() string {
-	for _, frame := range tag.Frames {
-		if frame.Id == "TYER" {
-			return frame.Data.String()
+func splitSlashValues(values []string) []string {
+	var split []string
+	for _, value := range values {
+		for _, s := range strings.Split(value, "/") {
+			split = append(split, strings.TrimSpace(s))
 		}
 	}
-	return ""
+	return split
 }
 
-func (tag *ID3v2Tag) Cover() []byte {
+// SetTextValues replaces the text frame with the given id. The values are
+// written NUL separated for ID3v2.4 and joined with "/" for earlier
+// versions. Passing no values removes the frame.
+func (tag *ID3v2Tag) SetTextValues(id string, values ...string) {
+	if len(values) == 0 {
+		tag.RemoveFrames(id)
+		return
+	}
+	tag.replaceFrame(tag.newFrame(id, NewTextFrame(values...)))
+}
+
+func (tag *ID3v2Tag) userTextID() string {
+	if tag.Header.Version == 2 {
+		return "TXX"
+	}
+	return "TXXX"
+}
+
+// UserTextValues returns the values of the user defined text frame (TXXX)
+// with the given description.
+func (tag *ID3v2Tag) UserTextValues(description string) []string {
 	for _, frame := range tag.Frames {
-		if frame.Id == "APIC" {
-			return []byte(frame.Data.String())
+		if d, ok := frame.Data.(*DescTextFrame); ok && frame.Id == tag.userTextID() && d.Description == description {
+			return d.Values
 		}
 	}
 	return nil
 }
+
+// SetUserTextValues replaces the user defined text frame (TXXX) with the
+// given description. Passing no values removes the frame.
+func (tag *ID3v2Tag) SetUserTextValues(description string, values ...string) {
+	if len(values) == 0 {
+		tag.RemoveFrame(func(frame *ID3v2Frame) bool {
+			d, ok := frame.Data.(*DescTextFrame)
+			return ok && frame.Id == tag.userTextID() && d.Description == description
+		})
+		return
+	}
+	tag.replaceFrame(tag.newFrame(tag.userTextID(), NewDescTextFrame(description, values...)))
+}
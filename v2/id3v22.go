@@ -5,25 +5,30 @@ func ParseID3v22FrameSize(data []byte) int {
 	return int(data[0])<<16 | int(data[1])<<8 | int(data[2])
 }
 
+// EncodeID3v22FrameSize is the inverse of ParseID3v22FrameSize.
+func EncodeID3v22FrameSize(size int) []byte {
+	return []byte{byte(size >> 16), byte(size >> 8), byte(size)}
+}
+
 // V22FrameTypeMap specifies the frame IDs and constructors allowed in ID3v2.2
 var V22FrameTypeMap = map[string]FrameType{
 	"BUF": {id: "BUF", description: "Recommended buffer size", constructor: ParseDataFrame},
-	"CNT": {id: "CNT", description: "Play counter", constructor: ParseDataFrame},
+	"CNT": {id: "CNT", description: "Play counter", constructor: ParsePlayCounterFrame},
 	"COM": {id: "COM", description: "Comments", constructor: ParseUnsynchTextFrame},
 	"CRA": {id: "CRA", description: "Audio encryption", constructor: ParseDataFrame},
 	"CRM": {id: "CRM", description: "Encrypted meta frame", constructor: ParseDataFrame},
 	"ETC": {id: "ETC", description: "Event timing codes", constructor: ParseDataFrame},
 	"EQU": {id: "EQU", description: "Equalization", constructor: ParseDataFrame},
-	"GEO": {id: "GEO", description: "General encapsulated object", constructor: ParseDataFrame},
-	"IPL": {id: "IPL", description: "Involved people list", constructor: ParseDataFrame},
+	"GEO": {id: "GEO", description: "General encapsulated object", constructor: ParseObjectFrame},
+	"IPL": {id: "IPL", description: "Involved people list", constructor: ParseInvolvedPeopleFrame},
 	"LNK": {id: "LNK", description: "Linked information", constructor: ParseDataFrame},
-	"MCI": {id: "MCI", description: "Music CD Identifier", constructor: ParseDataFrame},
-	"MLL": {id: "MLL", description: "MPEG location lookup table", constructor: ParseDataFrame},
-	"PIC": {id: "PIC", description: "Attached picture", constructor: ParseDataFrame},
-	"POP": {id: "POP", description: "Popularimeter", constructor: ParseDataFrame},
+	"MCI": {id: "MCI", description: "Music CD Identifier", constructor: ParseMusicCDIdentifierFrame},
+	"MLL": {id: "MLL", description: "MPEG location lookup table", constructor: ParseLocationLookupFrame},
+	"PIC": {id: "PIC", description: "Attached picture", constructor: ParseID3v22ImageFrame},
+	"POP": {id: "POP", description: "Popularimeter", constructor: ParsePopularimeterFrame},
 	"REV": {id: "REV", description: "Reverb", constructor: ParseDataFrame},
-	"RVA": {id: "RVA", description: "Relative volume adjustment", constructor: ParseDataFrame},
-	"SLT": {id: "SLT", description: "Synchronized lyric/text", constructor: ParseDataFrame},
+	"RVA": {id: "RVA", description: "Relative volume adjustment", constructor: ParseID3v23RelativeVolumeFrame},
+	"SLT": {id: "SLT", description: "Synchronized lyric/text", constructor: ParseSyncLyricsFrame},
 	"STC": {id: "STC", description: "Synced tempo codes", constructor: ParseDataFrame},
 	"TAL": {id: "TAL", description: "Album/Movie/Show title", constructor: ParseTextFrame},
 	"TBP": {id: "TBP", description: "BPM (Beats Per Minute)", constructor: ParseTextFrame},
@@ -61,23 +66,47 @@ var V22FrameTypeMap = map[string]FrameType{
 	"TXT": {id: "TXT", description: "Lyricist/text writer", constructor: ParseTextFrame},
 	"TXX": {id: "TXX", description: "User defined text information frame", constructor: ParseDescTextFrame},
 	"TYE": {id: "TYE", description: "Year", constructor: ParseTextFrame},
-	"UFI": {id: "UFI", description: "Unique file identifier", constructor: ParseDataFrame},
-	"ULT": {id: "ULT", description: "Unsychronized lyric/text transcription", constructor: ParseDataFrame},
-	"WAF": {id: "WAF", description: "Official audio file webpage", constructor: ParseDataFrame},
-	"WAR": {id: "WAR", description: "Official artist/performer webpage", constructor: ParseDataFrame},
-	"WAS": {id: "WAS", description: "Official audio source webpage", constructor: ParseDataFrame},
-	"WCM": {id: "WCM", description: "Commercial information", constructor: ParseDataFrame},
-	"WCP": {id: "WCP", description: "Copyright/Legal information", constructor: ParseDataFrame},
-	"WPB": {id: "WPB", description: "Publishers official webpage", constructor: ParseDataFrame},
-	"WXX": {id: "WXX", description: "User defined URL link frame", constructor: ParseDataFrame},
+	"UFI": {id: "UFI", description: "Unique file identifier", constructor: ParseIdFrame},
+	"ULT": {id: "ULT", description: "Unsychronized lyric/text transcription", constructor: ParseUnsynchTextFrame},
+	"WAF": {id: "WAF", description: "Official audio file webpage", constructor: ParseURLFrame},
+	"WAR": {id: "WAR", description: "Official artist/performer webpage", constructor: ParseURLFrame},
+	"WAS": {id: "WAS", description: "Official audio source webpage", constructor: ParseURLFrame},
+	"WCM": {id: "WCM", description: "Commercial information", constructor: ParseURLFrame},
+	"WCP": {id: "WCP", description: "Copyright/Legal information", constructor: ParseURLFrame},
+	"WPB": {id: "WPB", description: "Publishers official webpage", constructor: ParseURLFrame},
+	"WXX": {id: "WXX", description: "User defined URL link frame", constructor: ParseUserURLFrame},
 }
 
+// V22FrameMapping maps field names, as used by Get and Set, to ID3v2.2 frame IDs
 var V22FrameMapping = map[string]string{
-	"title":   "TT2",
-	"artist":  "TP1",
-	"album":   "TAL",
-	"year":    "TYE",
-	"comment": "COM",
-	"track":   "TRK",
-	"genre":   "TCO",
+	"title":            "TT2",
+	"subtitle":         "TT3",
+	"grouping":         "TT1",
+	"artist":           "TP1",
+	"albumartist":      "TP2",
+	"conductor":        "TP3",
+	"remixer":          "TP4",
+	"album":            "TAL",
+	"composer":         "TCM",
+	"lyricist":         "TXT",
+	"year":             "TYE",
+	"originalyear":     "TOR",
+	"comment":          "COM",
+	"lyrics":           "ULT",
+	"track":            "TRK",
+	"disc":             "TPA",
+	"genre":            "TCO",
+	"bpm":              "TBP",
+	"key":              "TKE",
+	"language":         "TLA",
+	"length":           "TLE",
+	"media":            "TMT",
+	"copyright":        "TCR",
+	"publisher":        "TPB",
+	"encodedby":        "TEN",
+	"encoder":          "TSS",
+	"isrc":             "TRC",
+	"originalartist":   "TOA",
+	"originalalbum":    "TOT",
+	"originalfilename": "TOF",
 }
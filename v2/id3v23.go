@@ -3,6 +3,8 @@ package v2
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
+	"strings"
 )
 
 func ParseID3v23FrameSize(buf []byte) int {
@@ -12,7 +14,17 @@ func ParseID3v23FrameSize(buf []byte) int {
 	return int(size)
 }
 
+// EncodeID3v23FrameSize is the inverse of ParseID3v23FrameSize.
+func EncodeID3v23FrameSize(size int) []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint32(buf, uint32(size))
+	return buf
+}
+
 // V23FrameTypeMap specifies the frame IDs and constructors allowed in ID3v2.3
+//
+// CHAP and CTOC are added by the init function of chapter.go, as their
+// constructors read sub-frames through this map.
 var V23FrameTypeMap = map[string]FrameType{
 	"AENC": {id: "AENC", description: "Audio encryption", constructor: ParseDataFrame},
 	"APIC": {id: "APIC", description: "Attached picture", constructor: ParseImageFrame},
@@ -21,21 +33,21 @@ var V23FrameTypeMap = map[string]FrameType{
 	"ENCR": {id: "ENCR", description: "Encryption method registration", constructor: ParseDataFrame},
 	"EQUA": {id: "EQUA", description: "Equalization", constructor: ParseDataFrame},
 	"ETCO": {id: "ETCO", description: "Event timing codes", constructor: ParseDataFrame},
-	"GEOB": {id: "GEOB", description: "General encapsulated object", constructor: ParseDataFrame},
+	"GEOB": {id: "GEOB", description: "General encapsulated object", constructor: ParseObjectFrame},
 	"GRID": {id: "GRID", description: "Group identification registration", constructor: ParseDataFrame},
-	"IPLS": {id: "IPLS", description: "Involved people list", constructor: ParseDataFrame},
+	"IPLS": {id: "IPLS", description: "Involved people list", constructor: ParseInvolvedPeopleFrame},
 	"LINK": {id: "LINK", description: "Linked information", constructor: ParseDataFrame},
-	"MCDI": {id: "MCDI", description: "Music CD identifier", constructor: ParseDataFrame},
-	"MLLT": {id: "MLLT", description: "MPEG location lookup table", constructor: ParseDataFrame},
+	"MCDI": {id: "MCDI", description: "Music CD identifier", constructor: ParseMusicCDIdentifierFrame},
+	"MLLT": {id: "MLLT", description: "MPEG location lookup table", constructor: ParseLocationLookupFrame},
 	"OWNE": {id: "OWNE", description: "Ownership frame", constructor: ParseDataFrame},
-	"PRIV": {id: "PRIV", description: "Private frame", constructor: ParseDataFrame},
-	"PCNT": {id: "PCNT", description: "Play counter", constructor: ParseDataFrame},
-	"POPM": {id: "POPM", description: "Popularimeter", constructor: ParseDataFrame},
+	"PRIV": {id: "PRIV", description: "Private frame", constructor: ParsePrivateFrame},
+	"PCNT": {id: "PCNT", description: "Play counter", constructor: ParsePlayCounterFrame},
+	"POPM": {id: "POPM", description: "Popularimeter", constructor: ParsePopularimeterFrame},
 	"POSS": {id: "POSS", description: "Position synchronisation frame", constructor: ParseDataFrame},
 	"RBUF": {id: "RBUF", description: "Recommended buffer size", constructor: ParseDataFrame},
-	"RVAD": {id: "RVAD", description: "Relative volume adjustment", constructor: ParseDataFrame},
+	"RVAD": {id: "RVAD", description: "Relative volume adjustment", constructor: ParseID3v23RelativeVolumeFrame},
 	"RVRB": {id: "RVRB", description: "Reverb", constructor: ParseDataFrame},
-	"SYLT": {id: "SYLT", description: "Synchronized lyric/text", constructor: ParseDataFrame},
+	"SYLT": {id: "SYLT", description: "Synchronized lyric/text", constructor: ParseSyncLyricsFrame},
 	"SYTC": {id: "SYTC", description: "Synchronized tempo codes", constructor: ParseDataFrame},
 	"TALB": {id: "TALB", description: "Album/Movie/Show title", constructor: ParseTextFrame},
 	"TBPM": {id: "TBPM", description: "BPM (beats per minute)", constructor: ParseTextFrame},
@@ -80,26 +92,51 @@ var V23FrameTypeMap = map[string]FrameType{
 	"USER": {id: "USER", description: "Terms of use", constructor: ParseDataFrame},
 	"TCMP": {id: "TCMP", description: "Part of a compilation (iTunes extension)", constructor: ParseTextFrame},
 	"USLT": {id: "USLT", description: "Unsychronized lyric/text transcription", constructor: ParseUnsynchTextFrame},
-	"WCOM": {id: "WCOM", description: "Commercial information", constructor: ParseDataFrame},
-	"WCOP": {id: "WCOP", description: "Copyright/Legal information", constructor: ParseDataFrame},
-	"WOAF": {id: "WOAF", description: "Official audio file webpage", constructor: ParseDataFrame},
-	"WOAR": {id: "WOAR", description: "Official artist/performer webpage", constructor: ParseDataFrame},
-	"WOAS": {id: "WOAS", description: "Official audio source webpage", constructor: ParseDataFrame},
-	"WORS": {id: "WORS", description: "Official internet radio station homepage", constructor: ParseDataFrame},
-	"WPAY": {id: "WPAY", description: "Payment", constructor: ParseDataFrame},
-	"WPUB": {id: "WPUB", description: "Publishers official webpage", constructor: ParseDataFrame},
-	"WXXX": {id: "WXXX", description: "User defined URL link frame", constructor: ParseDataFrame},
+	"WCOM": {id: "WCOM", description: "Commercial information", constructor: ParseURLFrame},
+	"WCOP": {id: "WCOP", description: "Copyright/Legal information", constructor: ParseURLFrame},
+	"WOAF": {id: "WOAF", description: "Official audio file webpage", constructor: ParseURLFrame},
+	"WOAR": {id: "WOAR", description: "Official artist/performer webpage", constructor: ParseURLFrame},
+	"WOAS": {id: "WOAS", description: "Official audio source webpage", constructor: ParseURLFrame},
+	"WORS": {id: "WORS", description: "Official internet radio station homepage", constructor: ParseURLFrame},
+	"WPAY": {id: "WPAY", description: "Payment", constructor: ParseURLFrame},
+	"WPUB": {id: "WPUB", description: "Publishers official webpage", constructor: ParseURLFrame},
+	"WXXX": {id: "WXXX", description: "User defined URL link frame", constructor: ParseUserURLFrame},
 	"TDRC": {id: "TDRC", description: "Recording date", constructor: ParseTextFrame},
 }
 
+// V23FrameMapping maps field names, as used by Get and Set, to ID3v2.3 frame IDs
 var V23FrameMapping = map[string]string{
-	"title":   "TIT2",
-	"artist":  "TPE1",
-	"album":   "TALB",
-	"year":    "TYER",
-	"comment": "COMM",
-	"track":   "TRCK",
-	"genre":   "TCON",
+	"title":            "TIT2",
+	"subtitle":         "TIT3",
+	"grouping":         "TIT1",
+	"artist":           "TPE1",
+	"albumartist":      "TPE2",
+	"conductor":        "TPE3",
+	"remixer":          "TPE4",
+	"album":            "TALB",
+	"composer":         "TCOM",
+	"lyricist":         "TEXT",
+	"year":             "TYER",
+	"originalyear":     "TORY",
+	"comment":          "COMM",
+	"lyrics":           "USLT",
+	"track":            "TRCK",
+	"disc":             "TPOS",
+	"genre":            "TCON",
+	"bpm":              "TBPM",
+	"key":              "TKEY",
+	"language":         "TLAN",
+	"length":           "TLEN",
+	"media":            "TMED",
+	"copyright":        "TCOP",
+	"publisher":        "TPUB",
+	"encodedby":        "TENC",
+	"encoder":          "TSSE",
+	"isrc":             "TSRC",
+	"originalartist":   "TOPE",
+	"originalalbum":    "TOAL",
+	"originalfilename": "TOFN",
+	"compilation":      "TCMP",
 }
 
 type DataFrame struct {
@@ -113,37 +150,247 @@ func ParseDataFrame(data []byte) (ID3v2Framer, error) {
 	return &DataFrame{}, nil
 }
 
-func ParseImageFrame(data []byte) (ID3v2Framer, error) {
-	return &DataFrame{}, nil
+// UnsynchTextFrame holds a comment (COMM) or unsynchronised lyrics (USLT)
+// frame: a language, a short content description and the text itself.
+type UnsynchTextFrame struct {
+	Language    string
+	Description string
+	Text        string
+}
+
+func (u *UnsynchTextFrame) String() string {
+	return u.Text
+}
+
+func (u *UnsynchTextFrame) Keys() []string {
+	return []string{u.Language + "\x00" + u.Description}
+}
+
+func (u *UnsynchTextFrame) Encode(version int) ([]byte, error) {
+	encoding := textEncoding(version, u.Description, u.Text)
+	data := []byte{encoding}
+	data = append(data, encodeLanguage(u.Language)...)
+	data = append(data, encodeString(encoding, u.Description)...)
+	data = append(data, stringTerminator(encoding)...)
+	data = append(data, encodeString(encoding, u.Text)...)
+	return data, nil
 }
 
 func ParseUnsynchTextFrame(data []byte) (ID3v2Framer, error) {
-	return &DataFrame{}, nil
+	if len(data) < 4 {
+		return nil, fmt.Errorf("unsynchronised text frame too short: %d bytes", len(data))
+	}
+	encoding := data[0]
+	desc, rest := splitString(encoding, data[4:])
+	description, err := decodeString(encoding, desc)
+	if err != nil {
+		return nil, err
+	}
+	text, err := decodeString(encoding, rest)
+	if err != nil {
+		return nil, err
+	}
+	return &UnsynchTextFrame{
+		Language:    string(data[1:4]),
+		Description: description,
+		Text:        text,
+	}, nil
+}
+
+// encodeLanguage returns the three byte ISO-639-2 language code, using
+// "XXX" when unknown.
+func encodeLanguage(language string) []byte {
+	if len(language) != 3 {
+		return []byte("XXX")
+	}
+	return []byte(language)
+}
+
+// DescTextFrame holds a user defined text frame (TXXX): a description
+// identifying the frame and its values, which ID3v2.4 allows several of.
+type DescTextFrame struct {
+	Description string
+	Values      []string
+}
+
+func (d *DescTextFrame) String() string {
+	return d.Text()
+}
+
+// Text joins the values with "/" for display.
+func (d *DescTextFrame) Text() string {
+	return strings.Join(d.Values, "/")
+}
+
+func (d *DescTextFrame) Keys() []string {
+	return []string{d.Description}
+}
+
+// NewDescTextFrame creates a user defined text frame holding the given values.
+func NewDescTextFrame(description string, values ...string) *DescTextFrame {
+	return &DescTextFrame{
+		Description: description,
+		Values:      values,
+	}
+}
+
+func (d *DescTextFrame) Encode(version int) ([]byte, error) {
+	values := d.Values
+	encoding := textEncoding(version, append([]string{d.Description}, values...)...)
+	data := []byte{encoding}
+	data = append(data, encodeString(encoding, d.Description)...)
+	data = append(data, stringTerminator(encoding)...)
+	return append(data, encodeTextValues(encoding, version, values)...), nil
 }
 
 func ParseDescTextFrame(data []byte) (ID3v2Framer, error) {
-	return &DataFrame{}, nil
+	if len(data) < 1 {
+		return nil, fmt.Errorf("user defined text frame too short")
+	}
+	desc, rest := splitString(data[0], data[1:])
+	description, err := decodeString(data[0], desc)
+	if err != nil {
+		return nil, err
+	}
+	values, err := parseStrings(append([]byte{data[0]}, rest...))
+	if err != nil {
+		return nil, err
+	}
+	return NewDescTextFrame(description, values...), nil
+}
+
+// IdFrame holds a unique file identifier (UFID) frame: the owner, usually a
+// URL of the database issuing the identifier, and up to 64 bytes of binary
+// identifier.
+type IdFrame struct {
+	Owner      string
+	Identifier []byte
+}
+
+func (i *IdFrame) String() string {
+	return string(i.Identifier)
+}
+
+func (i *IdFrame) Keys() []string {
+	return []string{i.Owner}
+}
+
+func (i *IdFrame) Encode(version int) ([]byte, error) {
+	data := append(encodeString(EncodingISO8859_1, i.Owner), 0)
+	return append(data, i.Identifier...), nil
 }
 
 func ParseIdFrame(data []byte) (ID3v2Framer, error) {
-	return &DataFrame{}, nil
+	owner, identifier := splitString(EncodingISO8859_1, data)
+	return &IdFrame{
+		Owner:      ISO8859_1ToUTF8(owner),
+		Identifier: identifier,
+	}, nil
 }
 
+// TextFrame holds the content of a text information frame. ID3v2.4 allows
+// several values separated by NUL, which are kept in Values.
 type TextFrame struct {
-	Text string
+	Values []string
 }
 
 func (t *TextFrame) String() string {
-	return t.Text
+	return t.Text()
+}
+
+// Text joins the values with "/" for display.
+func (t *TextFrame) Text() string {
+	return strings.Join(t.Values, "/")
+}
+
+// NewTextFrame creates a text frame holding the given values.
+func NewTextFrame(values ...string) *TextFrame {
+	return &TextFrame{Values: values}
+}
+
+// Encode writes the values NUL separated for ID3v2.4 and joined with "/"
+// for earlier versions, which only allow a single string.
+func (t *TextFrame) Encode(version int) ([]byte, error) {
+	encoding := textEncoding(version, t.Values...)
+	return append([]byte{encoding}, encodeTextValues(encoding, version, t.Values)...), nil
+}
+
+// encodeTextValues writes values NUL separated for ID3v2.4 and joined with
+// "/" for earlier versions.
+func encodeTextValues(encoding byte, version int, values []string) []byte {
+	if version < 4 {
+		return encodeString(encoding, strings.Join(values, "/"))
+	}
+	var data []byte
+	for i, value := range values {
+		if i > 0 {
+			data = append(data, stringTerminator(encoding)...)
+		}
+		data = append(data, encodeString(encoding, value)...)
+	}
+	return data
 }
 
 func ParseTextFrame(data []byte) (ID3v2Framer, error) {
-	str, err := parseString(data)
+	values, err := parseStrings(data)
 	if err != nil {
 		return nil, err
 	}
-	text := &TextFrame{
-		Text: str,
+	return NewTextFrame(values...), nil
+}
+
+// URLFrame holds a URL link frame, such as WOAR or WCOM, whose only content
+// is an ISO-8859-1 URL.
+type URLFrame struct {
+	URL string
+}
+
+func (u *URLFrame) String() string {
+	return u.URL
+}
+
+func (u *URLFrame) Encode(version int) ([]byte, error) {
+	return encodeString(EncodingISO8859_1, u.URL), nil
+}
+
+func ParseURLFrame(data []byte) (ID3v2Framer, error) {
+	return &URLFrame{URL: strings.TrimRight(ISO8859_1ToUTF8(data), "\u0000")}, nil
+}
+
+// UserURLFrame holds a user defined URL link frame (WXXX): a description
+// identifying the frame and the URL, which is always ISO-8859-1.
+type UserURLFrame struct {
+	Description string
+	URL         string
+}
+
+func (u *UserURLFrame) String() string {
+	return u.URL
+}
+
+func (u *UserURLFrame) Keys() []string {
+	return []string{u.Description}
+}
+
+func (u *UserURLFrame) Encode(version int) ([]byte, error) {
+	encoding := textEncoding(version, u.Description)
+	data := []byte{encoding}
+	data = append(data, encodeString(encoding, u.Description)...)
+	data = append(data, stringTerminator(encoding)...)
+	return append(data, encodeString(EncodingISO8859_1, u.URL)...), nil
+}
+
+func ParseUserURLFrame(data []byte) (ID3v2Framer, error) {
+	if len(data) < 1 {
+		return nil, fmt.Errorf("user defined URL frame too short")
+	}
+	desc, url := splitString(data[0], data[1:])
+	description, err := decodeString(data[0], desc)
+	if err != nil {
+		return nil, err
 	}
-	return text, nil
+	return &UserURLFrame{
+		Description: description,
+		URL:         strings.TrimRight(ISO8859_1ToUTF8(url), "\u0000"),
+	}, nil
 }
@@ -4,14 +4,81 @@ func ParseID3v24FrameSize(data []byte) int {
 	return int(parseSize(data))
 }
 
-var V24FrameTypeMap = V23FrameTypeMap
+// EncodeID3v24FrameSize is the inverse of ParseID3v24FrameSize.
+func EncodeID3v24FrameSize(size int) []byte {
+	return encodeSize(int32(size), 4)
+}
+
+// V24FrameTypeMap specifies the frame IDs and constructors allowed in ID3v2.4.
+// It extends V23FrameTypeMap with the frames introduced in ID3v2.4. The
+// frames ID3v2.4 drops are kept since loosely written tags still use them.
+// CHAP and CTOC are added by the init function of chapter.go, as for
+// V23FrameTypeMap.
+var V24FrameTypeMap = func() map[string]FrameType {
+	m := map[string]FrameType{
+		"ASPI": {id: "ASPI", description: "Audio seek point index", constructor: ParseDataFrame},
+		"EQU2": {id: "EQU2", description: "Equalisation (2)", constructor: ParseDataFrame},
+		"SEEK": {id: "SEEK", description: "Seek frame", constructor: ParseDataFrame},
+		"SIGN": {id: "SIGN", description: "Signature frame", constructor: ParseDataFrame},
+		"TPRO": {id: "TPRO", description: "Produced notice", constructor: ParseTextFrame},
+		"TDEN": {id: "TDEN", description: "Encoding time", constructor: ParseTextFrame},
+		"TDOR": {id: "TDOR", description: "Original release time", constructor: ParseTextFrame},
+		"TDRC": {id: "TDRC", description: "Recording time", constructor: ParseTextFrame},
+		"TDRL": {id: "TDRL", description: "Release time", constructor: ParseTextFrame},
+		"TDTG": {id: "TDTG", description: "Tagging time", constructor: ParseTextFrame},
+		"TIPL": {id: "TIPL", description: "Involved people list", constructor: ParseInvolvedPeopleFrame},
+		"TMCL": {id: "TMCL", description: "Musician credits list", constructor: ParseInvolvedPeopleFrame},
+		"RVA2": {id: "RVA2", description: "Relative volume adjustment (2)", constructor: ParseRelativeVolumeFrame},
+		"TMOO": {id: "TMOO", description: "Mood", constructor: ParseTextFrame},
+		"TSOA": {id: "TSOA", description: "Album sort order", constructor: ParseTextFrame},
+		"TSOP": {id: "TSOP", description: "Performer sort order", constructor: ParseTextFrame},
+		"TSOT": {id: "TSOT", description: "Title sort order", constructor: ParseTextFrame},
+		"TSST": {id: "TSST", description: "Set subtitle", constructor: ParseTextFrame},
+	}
+	for id, t := range V23FrameTypeMap {
+		if _, ok := m[id]; !ok {
+			m[id] = t
+		}
+	}
+	return m
+}()
 
+// V24FrameMapping maps field names, as used by Get and Set, to ID3v2.4 frame IDs
 var V24FrameMapping = map[string]string{
-	"title":   "TIT2",
-	"artist":  "TPE1",
-	"album":   "TALB",
-	"year":    "TDRC",
-	"comment": "COMM",
-	"track":   "TRCK",
-	"genre":   "TCON",
+	"title":            "TIT2",
+	"subtitle":         "TIT3",
+	"grouping":         "TIT1",
+	"artist":           "TPE1",
+	"albumartist":      "TPE2",
+	"conductor":        "TPE3",
+	"remixer":          "TPE4",
+	"album":            "TALB",
+	"composer":         "TCOM",
+	"lyricist":         "TEXT",
+	"year":             "TDRC",
+	"originalyear":     "TDOR",
+	"comment":          "COMM",
+	"lyrics":           "USLT",
+	"track":            "TRCK",
+	"disc":             "TPOS",
+	"genre":            "TCON",
+	"bpm":              "TBPM",
+	"key":              "TKEY",
+	"language":         "TLAN",
+	"length":           "TLEN",
+	"media":            "TMED",
+	"copyright":        "TCOP",
+	"publisher":        "TPUB",
+	"encodedby":        "TENC",
+	"encoder":          "TSSE",
+	"isrc":             "TSRC",
+	"originalartist":   "TOPE",
+	"originalalbum":    "TOAL",
+	"originalfilename": "TOFN",
+	"compilation":      "TCMP",
+	"mood":             "TMOO",
+	"setsubtitle":      "TSST",
+	"albumsort":        "TSOA",
+	"artistsort":       "TSOP",
+	"titlesort":        "TSOT",
 }
@@ -0,0 +1,123 @@
+package v2
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestTextFrameEncode(t *testing.T) {
+	tests := []struct {
+		version int
+		values  []string
+		want    []byte
+	}{
+		{3, []string{"Alice", "Bob"}, []byte("\x00Alice/Bob")},
+		{4, []string{"Alice", "Bob"}, []byte("\x03Alice\x00Bob")},
+		{3, []string{"Ωmega"}, []byte("\x01\xff\xfe\xa9\x03m\x00e\x00g\x00a\x00")},
+	}
+	for _, tt := range tests {
+		got, err := NewTextFrame(tt.values...).Encode(tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("v2.%d %q: got %q, want %q", tt.version, tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestTextValuesRoundTrip(t *testing.T) {
+	for _, version := range []int{2, 3, 4} {
+		tag := NewID3v2Tag(version)
+		artist := tag.frameMapping()["artist"]
+		title := tag.frameMapping()["title"]
+		tag.SetTextValues(artist, "Alice", "Bob")
+		tag.SetTextValues(title, "Either/Or")
+		data, err := tag.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		read, err := Read(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := read.TextValues(artist); !reflect.DeepEqual(got, []string{"Alice", "Bob"}) {
+			t.Errorf("v2.%d artists: got %q", version, got)
+		}
+		if got := read.TextValues(title); !reflect.DeepEqual(got, []string{"Either/Or"}) {
+			t.Errorf("v2.%d title: got %q", version, got)
+		}
+	}
+}
+
+func TestParseTextFrameTrailingNUL(t *testing.T) {
+	frame, err := ParseTextFrame([]byte("\x03One\x00Two\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := frame.(*TextFrame).Values; !reflect.DeepEqual(got, []string{"One", "Two"}) {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestTextFrameEdit(t *testing.T) {
+	tag := NewID3v2Tag(3)
+	tag.SetTextValues("TIT2", "Old")
+	tag.SetUserTextValues("MOOD", "Old")
+	tag.findFrame("TIT2").Data.(*TextFrame).Values = []string{"New"}
+	tag.findFrame("TXXX").Data.(*DescTextFrame).Values = []string{"New", "Newer"}
+
+	data, err := tag.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := read.findFrame("TIT2").Data.(*TextFrame).Text(); got != "New" {
+		t.Errorf("title = %q", got)
+	}
+	if got := read.findFrame("TXXX").Data.(*DescTextFrame).Text(); got != "New/Newer" {
+		t.Errorf("user text = %q", got)
+	}
+}
+
+func TestV24OnlyAndUnknownFrames(t *testing.T) {
+	frames := []*ID3v2Frame{
+		{Id: "TPRO", Raw: []byte("\x032024 Label")},
+		{Id: "ASPI", Raw: []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 1, 8, 0}},
+		{Id: "EQU2", Raw: []byte("\x00eq\x00\x00\x10\x00\x00")},
+		{Id: "SEEK", Raw: []byte{0, 0, 0x10, 0}},
+		{Id: "SIGN", Raw: []byte{1, 0xAB}},
+		{Id: "XYZ1", Raw: []byte("experimental")},
+	}
+	data := rawTag(t, 4, frames...)
+	tag, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tag.Frames) != len(frames) {
+		t.Fatalf("got %d frames, want %d", len(tag.Frames), len(frames))
+	}
+	if got := tag.TextValues("TPRO"); len(got) != 1 || got[0] != "2024 Label" {
+		t.Errorf("TPRO = %q", got)
+	}
+	written, err := tag.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written, data) {
+		t.Error("frames not written back unchanged")
+	}
+
+	for _, id := range []string{"EQU2", "SIGN"} {
+		if err := tag.AddFrame(&ID3v2Frame{Id: id, Data: &DataFrame{}, Raw: []byte{2}}); err != nil {
+			t.Errorf("%s: %s", id, err)
+		}
+	}
+	if err := tag.AddFrame(&ID3v2Frame{Id: "XYZ1", Data: &DataFrame{}}); err == nil {
+		t.Error("unknown frame added")
+	}
+}
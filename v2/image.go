@@ -0,0 +1,136 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Picture types of attached picture frames.
+//
+// Refer to section 4.15 of http://id3.org/id3v2.3.0
+const (
+	PictureTypeOther byte = iota
+	PictureTypeFileIcon
+	PictureTypeOtherFileIcon
+	PictureTypeFrontCover
+	PictureTypeBackCover
+	PictureTypeLeaflet
+	PictureTypeMedia
+	PictureTypeLeadArtist
+	PictureTypeArtist
+	PictureTypeConductor
+	PictureTypeBand
+	PictureTypeComposer
+	PictureTypeLyricist
+	PictureTypeRecordingLocation
+	PictureTypeDuringRecording
+	PictureTypeDuringPerformance
+	PictureTypeVideoCapture
+	PictureTypeBrightFish
+	PictureTypeIllustration
+	PictureTypeBandLogo
+	PictureTypePublisherLogo
+)
+
+// ImageFrame holds an attached picture, APIC in ID3v2.3 and ID3v2.4 or PIC
+// in ID3v2.2. ID3v2.2 stores a three letter image format instead of a MIME
+// type, which is translated on reading and writing.
+type ImageFrame struct {
+	MIMEType    string
+	PictureType byte
+	Description string
+	Data        []byte
+}
+
+func (i *ImageFrame) String() string {
+	return i.Description
+}
+
+// Keys makes the description unique among pictures, as well as the file
+// icons and the front cover.
+func (i *ImageFrame) Keys() []string {
+	keys := []string{"description:" + i.Description}
+	switch i.PictureType {
+	case PictureTypeFileIcon, PictureTypeOtherFileIcon, PictureTypeFrontCover:
+		keys = append(keys, fmt.Sprintf("type:%d", i.PictureType))
+	}
+	return keys
+}
+
+func (i *ImageFrame) Encode(version int) ([]byte, error) {
+	encoding := textEncoding(version, i.Description)
+	data := []byte{encoding}
+	if version == 2 {
+		data = append(data, mimeToImageFormat(i.MIMEType)...)
+	} else {
+		data = append(data, i.MIMEType...)
+		data = append(data, 0)
+	}
+	data = append(data, i.PictureType)
+	data = append(data, encodeString(encoding, i.Description)...)
+	data = append(data, stringTerminator(encoding)...)
+	return append(data, i.Data...), nil
+}
+
+// ParseImageFrame parses an ID3v2.3 or ID3v2.4 APIC frame.
+func ParseImageFrame(data []byte) (ID3v2Framer, error) {
+	if len(data) < 1 {
+		return nil, fmt.Errorf("attached picture frame too short")
+	}
+	mime, rest := splitString(EncodingISO8859_1, data[1:])
+	return parseImage(data[0], string(mime), rest)
+}
+
+// ParseID3v22ImageFrame parses an ID3v2.2 PIC frame.
+func ParseID3v22ImageFrame(data []byte) (ID3v2Framer, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("attached picture frame too short")
+	}
+	return parseImage(data[0], imageFormatToMIME(string(data[1:4])), data[4:])
+}
+
+func parseImage(encoding byte, mime string, data []byte) (ID3v2Framer, error) {
+	if len(data) < 1 {
+		return nil, fmt.Errorf("attached picture frame too short")
+	}
+	desc, image := splitString(encoding, data[1:])
+	description, err := decodeString(encoding, desc)
+	if err != nil {
+		return nil, err
+	}
+	return &ImageFrame{
+		MIMEType:    mime,
+		PictureType: data[0],
+		Description: description,
+		Data:        image,
+	}, nil
+}
+
+var imageFormats = map[string]string{
+	"JPG": "image/jpeg",
+	"PNG": "image/png",
+	"GIF": "image/gif",
+	"BMP": "image/bmp",
+	"-->": "-->",
+}
+
+func imageFormatToMIME(format string) string {
+	if mime, ok := imageFormats[strings.ToUpper(format)]; ok {
+		return mime
+	}
+	return "image/" + strings.ToLower(format)
+}
+
+func mimeToImageFormat(mime string) string {
+	mime = strings.ToLower(mime)
+	if mime == "image/jpg" {
+		return "JPG"
+	}
+	for format, m := range imageFormats {
+		if m == mime {
+			return format
+		}
+	}
+	format := strings.ToUpper(strings.TrimPrefix(mime, "image/"))
+	return (format + "   ")[:3]
+}
@@ -0,0 +1,150 @@
+package v2
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// CDLeadOutTrack is the track number of the lead-out in a CD table of
+// contents.
+const CDLeadOutTrack = 0xAA
+
+// cdPregap is the two second pregap, in frames of 1/75 s, which LBA offsets
+// do not count.
+const cdPregap = 150
+
+// CDTrack is a table of contents entry: the track number, its control
+// nibble telling audio from data tracks and its logical block address.
+type CDTrack struct {
+	Number  int
+	Control byte
+	Offset  uint32
+}
+
+// IsData reports whether the track is a data track.
+func (t CDTrack) IsData() bool {
+	return t.Control&0x04 != 0
+}
+
+// MusicCDIdentifierFrame holds a music CD identifier (MCDI, MCI in ID3v2.2)
+// frame: the CD table of contents as returned by the READ TOC command.
+//
+// Refer to section 4.5 of http://id3.org/id3v2.3.0
+type MusicCDIdentifierFrame struct {
+	FirstTrack int
+	LastTrack  int
+	Tracks     []CDTrack
+	LeadOut    uint32
+}
+
+func (m *MusicCDIdentifierFrame) String() string {
+	return m.FreeDBDiscID()
+}
+
+func (m *MusicCDIdentifierFrame) Encode(version int) ([]byte, error) {
+	data := make([]byte, 4, 4+8*(len(m.Tracks)+1))
+	data[2], data[3] = byte(m.FirstTrack), byte(m.LastTrack)
+	entry := func(number int, control byte, offset uint32) {
+		data = append(data, 0, 0x10|control, byte(number), 0)
+		data = binary.BigEndian.AppendUint32(data, offset)
+	}
+	for _, t := range m.Tracks {
+		entry(t.Number, t.Control, t.Offset)
+	}
+	entry(CDLeadOutTrack, 0, m.LeadOut)
+	binary.BigEndian.PutUint16(data, uint16(len(data)-2))
+	return data, nil
+}
+
+// ParseMusicCDIdentifierFrame parses an MCDI frame. Some taggers store
+// other data than a table of contents, which is kept undecoded as a
+// DataFrame.
+func ParseMusicCDIdentifierFrame(data []byte) (ID3v2Framer, error) {
+	if len(data) < 4 {
+		return ParseDataFrame(data)
+	}
+	m := &MusicCDIdentifierFrame{
+		FirstTrack: int(data[2]),
+		LastTrack:  int(data[3]),
+	}
+	length := int(binary.BigEndian.Uint16(data)) + 2
+	if length > len(data) {
+		length = len(data)
+	}
+	leadOut := false
+	for rest := data[4:length]; len(rest) >= 8; rest = rest[8:] {
+		number, offset := int(rest[2]), binary.BigEndian.Uint32(rest[4:8])
+		if number == CDLeadOutTrack {
+			m.LeadOut, leadOut = offset, true
+			continue
+		}
+		m.Tracks = append(m.Tracks, CDTrack{Number: number, Control: rest[1] & 0x0F, Offset: offset})
+	}
+	if !leadOut || len(m.Tracks) == 0 {
+		return ParseDataFrame(data)
+	}
+	return m, nil
+}
+
+// FreeDBDiscID computes the freedb, formerly CDDB, disc ID as 8 hexadecimal
+// digits.
+//
+// Refer to https://en.wikipedia.org/wiki/CDDB#Example_calculation_of_a_CDDB1_(FreeDB)_disc_ID
+func (m *MusicCDIdentifierFrame) FreeDBDiscID() string {
+	if len(m.Tracks) == 0 {
+		return ""
+	}
+	n := 0
+	for _, t := range m.Tracks {
+		for seconds := (t.Offset + cdPregap) / 75; seconds > 0; seconds /= 10 {
+			n += int(seconds % 10)
+		}
+	}
+	length := (m.LeadOut+cdPregap)/75 - (m.Tracks[0].Offset+cdPregap)/75
+	return fmt.Sprintf("%08x", uint32(n%0xFF)<<24|length<<8|uint32(len(m.Tracks)))
+}
+
+// MusicBrainzDiscID computes the MusicBrainz disc ID. As MusicBrainz only
+// considers the audio session, a trailing data track of an enhanced CD is
+// left out, the audio session then ending 11400 frames before it.
+//
+// Refer to https://musicbrainz.org/doc/Disc_ID_Calculation
+func (m *MusicCDIdentifierFrame) MusicBrainzDiscID() string {
+	tracks := m.Tracks
+	last := m.LastTrack
+	leadOut := m.LeadOut
+	if n := len(tracks); n > 1 && tracks[n-1].IsData() && tracks[n-1].Offset >= 11400 {
+		leadOut = tracks[n-1].Offset - 11400
+		last = tracks[n-2].Number
+		tracks = tracks[:n-1]
+	}
+
+	var offsets [100]uint32
+	offsets[0] = leadOut + cdPregap
+	for _, t := range tracks {
+		if t.Number >= 1 && t.Number <= 99 {
+			offsets[t.Number] = t.Offset + cdPregap
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%02X%02X", m.FirstTrack, last)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%08X", offset)
+	}
+	sum := sha1.Sum([]byte(b.String()))
+	id := base64.StdEncoding.EncodeToString(sum[:])
+	return strings.NewReplacer("+", ".", "/", "_", "=", "-").Replace(id)
+}
+
+// MusicCDIdentifier returns the music CD identifier frame of the tag, or nil.
+func (tag *ID3v2Tag) MusicCDIdentifier() *MusicCDIdentifierFrame {
+	for _, frame := range tag.Frames {
+		if m, ok := frame.Data.(*MusicCDIdentifierFrame); ok {
+			return m
+		}
+	}
+	return nil
+}
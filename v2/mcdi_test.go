@@ -0,0 +1,64 @@
+package v2
+
+import (
+	"reflect"
+	"testing"
+)
+
+// exampleTOC is the table of contents of the MusicBrainz disc ID
+// documentation, whose offsets include the two second pregap.
+func exampleTOC() *MusicCDIdentifierFrame {
+	m := &MusicCDIdentifierFrame{FirstTrack: 1, LastTrack: 6, LeadOut: 95462 - cdPregap}
+	for i, offset := range []uint32{150, 15363, 32314, 46592, 63414, 80489} {
+		m.Tracks = append(m.Tracks, CDTrack{Number: i + 1, Offset: offset - cdPregap})
+	}
+	return m
+}
+
+func TestDiscIDs(t *testing.T) {
+	m := exampleTOC()
+	if got, want := m.MusicBrainzDiscID(), "49HHV7Eb8UKF3aQiNmu1GR8vKTY-"; got != want {
+		t.Errorf("MusicBrainz disc ID = %s, want %s", got, want)
+	}
+	// 52 as the sum of the digits of the track start times in seconds, 1270
+	// seconds of audio and 6 tracks.
+	if got, want := m.FreeDBDiscID(), "3404f606"; got != want {
+		t.Errorf("freedb disc ID = %s, want %s", got, want)
+	}
+
+	// The data track of an enhanced CD does not change the MusicBrainz ID.
+	enhanced := exampleTOC()
+	enhanced.LastTrack = 7
+	enhanced.Tracks = append(enhanced.Tracks, CDTrack{Number: 7, Control: 0x04, Offset: m.LeadOut + 11400})
+	enhanced.LeadOut = m.LeadOut + 30000
+	if got, want := enhanced.MusicBrainzDiscID(), m.MusicBrainzDiscID(); got != want {
+		t.Errorf("enhanced CD MusicBrainz disc ID = %s, want %s", got, want)
+	}
+}
+
+func TestMusicCDIdentifierRoundTrip(t *testing.T) {
+	m := exampleTOC()
+	data, err := m.Encode(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 4+8*7 {
+		t.Fatalf("encoded %d bytes", len(data))
+	}
+	parsed, err := ParseMusicCDIdentifierFrame(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, m) {
+		t.Errorf("got %+v, want %+v", parsed, m)
+	}
+
+	// Other data is kept undecoded.
+	parsed, err = ParseMusicCDIdentifierFrame([]byte("not a TOC"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parsed.(*DataFrame); !ok {
+		t.Errorf("got %#v", parsed)
+	}
+}
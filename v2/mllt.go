@@ -0,0 +1,139 @@
+package v2
+
+import (
+	"fmt"
+	"time"
+)
+
+// LocationDeviation is the deviation of one reference of an MPEG location
+// lookup table from the nominal distance between references.
+type LocationDeviation struct {
+	Bytes        uint32
+	Milliseconds uint32
+}
+
+// LocationLookupFrame holds an MPEG location lookup table (MLLT, MLL in
+// ID3v2.2) frame. References are placed every FramesBetween frames, each
+// BytesBetween bytes and MillisecondsBetween milliseconds after the previous
+// one plus its deviation. Byte positions are relative to the first frame
+// after the tag.
+//
+// Refer to section 4.7 of http://id3.org/id3v2.3.0
+type LocationLookupFrame struct {
+	FramesBetween       uint16
+	BytesBetween        uint32
+	MillisecondsBetween uint32
+	BytesBits           byte
+	MillisecondsBits    byte
+	Deviations          []LocationDeviation
+}
+
+func (m *LocationLookupFrame) String() string {
+	return fmt.Sprintf("%d references", len(m.Deviations))
+}
+
+func (m *LocationLookupFrame) Encode(version int) ([]byte, error) {
+	if (m.BytesBits+m.MillisecondsBits)%4 != 0 {
+		return nil, fmt.Errorf("MPEG location lookup table deviation bits must be a multiple of 4")
+	}
+	if m.BytesBetween > 0xFFFFFF || m.MillisecondsBetween > 0xFFFFFF {
+		return nil, fmt.Errorf("MPEG location lookup table reference distance exceeds 24 bits")
+	}
+	data := []byte{
+		byte(m.FramesBetween >> 8), byte(m.FramesBetween),
+		byte(m.BytesBetween >> 16), byte(m.BytesBetween >> 8), byte(m.BytesBetween),
+		byte(m.MillisecondsBetween >> 16), byte(m.MillisecondsBetween >> 8), byte(m.MillisecondsBetween),
+		m.BytesBits, m.MillisecondsBits,
+	}
+	var acc uint64
+	var n uint
+	put := func(v uint32, bits byte) {
+		for i := int(bits) - 1; i >= 0; i-- {
+			acc = acc<<1 | uint64(v>>uint(i)&1)
+			if n++; n == 8 {
+				data = append(data, byte(acc))
+				acc, n = 0, 0
+			}
+		}
+	}
+	for _, d := range m.Deviations {
+		put(d.Bytes, m.BytesBits)
+		put(d.Milliseconds, m.MillisecondsBits)
+	}
+	if n > 0 {
+		data = append(data, byte(acc<<(8-n)))
+	}
+	return data, nil
+}
+
+// ParseLocationLookupFrame parses an MLLT frame. The deviations are padded
+// to a whole byte, so trailing zero bits shorter than a byte are taken as
+// padding rather than a last deviation of zero.
+func ParseLocationLookupFrame(data []byte) (ID3v2Framer, error) {
+	if len(data) < 10 {
+		return nil, fmt.Errorf("MPEG location lookup table frame too short")
+	}
+	m := &LocationLookupFrame{
+		FramesBetween:       uint16(data[0])<<8 | uint16(data[1]),
+		BytesBetween:        uint32(data[2])<<16 | uint32(data[3])<<8 | uint32(data[4]),
+		MillisecondsBetween: uint32(data[5])<<16 | uint32(data[6])<<8 | uint32(data[7]),
+		BytesBits:           data[8],
+		MillisecondsBits:    data[9],
+	}
+	if m.BytesBits > 32 || m.MillisecondsBits > 32 {
+		return nil, fmt.Errorf("invalid MPEG location lookup table deviation bits")
+	}
+	bits := int(m.BytesBits) + int(m.MillisecondsBits)
+	if bits == 0 {
+		return m, nil
+	}
+	rest := data[10:]
+	pos := 0
+	get := func(width byte) uint32 {
+		var v uint32
+		for i := 0; i < int(width); i++ {
+			bit := rest[pos/8] >> (7 - uint(pos%8)) & 1
+			v = v<<1 | uint32(bit)
+			pos++
+		}
+		return v
+	}
+	for pos+bits <= 8*len(rest) {
+		if remaining := 8*len(rest) - pos; remaining < 8 && rest[len(rest)-1]&(1<<uint(remaining)-1) == 0 {
+			break
+		}
+		m.Deviations = append(m.Deviations, LocationDeviation{
+			Bytes:        get(m.BytesBits),
+			Milliseconds: get(m.MillisecondsBits),
+		})
+	}
+	return m, nil
+}
+
+// Offset returns the byte position, relative to the first frame after the
+// tag, of the last reference at or before d, and the time of that
+// reference.
+func (m *LocationLookupFrame) Offset(d time.Duration) (int64, time.Duration) {
+	var offset int64
+	var t time.Duration
+	for _, dev := range m.Deviations {
+		next := t + time.Duration(m.MillisecondsBetween+dev.Milliseconds)*time.Millisecond
+		if next > d {
+			break
+		}
+		offset += int64(m.BytesBetween + dev.Bytes)
+		t = next
+	}
+	return offset, t
+}
+
+// LocationLookupTable returns the MPEG location lookup table frame of the
+// tag, or nil.
+func (tag *ID3v2Tag) LocationLookupTable() *LocationLookupFrame {
+	for _, frame := range tag.Frames {
+		if m, ok := frame.Data.(*LocationLookupFrame); ok {
+			return m
+		}
+	}
+	return nil
+}
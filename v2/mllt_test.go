@@ -0,0 +1,96 @@
+package v2
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLocationLookupRoundTrip(t *testing.T) {
+	for _, m := range []*LocationLookupFrame{
+		{
+			FramesBetween: 10, BytesBetween: 4180, MillisecondsBetween: 261,
+			BytesBits: 8, MillisecondsBits: 4,
+			Deviations: []LocationDeviation{{12, 1}, {0, 0}, {200, 15}},
+		},
+		{
+			FramesBetween: 1, BytesBetween: 0xFFFFFF, MillisecondsBetween: 26,
+			BytesBits: 16, MillisecondsBits: 8,
+			Deviations: []LocationDeviation{{0xFFFF, 3}, {1, 0}},
+		},
+	} {
+		data, err := m.Encode(4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bits := len(m.Deviations) * int(m.BytesBits+m.MillisecondsBits)
+		if want := 10 + (bits+7)/8; len(data) != want {
+			t.Errorf("encoded %d bytes, want %d", len(data), want)
+		}
+		parsed, err := ParseLocationLookupFrame(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(parsed, m) {
+			t.Errorf("got %+v, want %+v", parsed, m)
+		}
+	}
+}
+
+func TestLocationLookupErrors(t *testing.T) {
+	for _, m := range []*LocationLookupFrame{
+		{BytesBits: 8, MillisecondsBits: 2},
+		{BytesBetween: 0x1000000, BytesBits: 8, MillisecondsBits: 8},
+		{MillisecondsBetween: 0x1000000, BytesBits: 8, MillisecondsBits: 8},
+	} {
+		if _, err := m.Encode(4); err == nil {
+			t.Errorf("%+v encoded", m)
+		}
+	}
+	if _, err := ParseLocationLookupFrame(make([]byte, 9)); err == nil {
+		t.Error("short frame parsed")
+	}
+	if _, err := ParseLocationLookupFrame([]byte{0, 1, 0, 0, 1, 0, 0, 1, 40, 8}); err == nil {
+		t.Error("40 bit deviations parsed")
+	}
+}
+
+func TestLocationLookupOffset(t *testing.T) {
+	m := &LocationLookupFrame{
+		FramesBetween: 2, BytesBetween: 800, MillisecondsBetween: 50,
+		BytesBits: 8, MillisecondsBits: 8,
+		Deviations: []LocationDeviation{{34, 2}, {0, 0}, {40, 3}},
+	}
+	tests := []struct {
+		d      time.Duration
+		offset int64
+		t      time.Duration
+	}{
+		{0, 0, 0},
+		{51 * time.Millisecond, 0, 0},
+		{52 * time.Millisecond, 834, 52 * time.Millisecond},
+		{120 * time.Millisecond, 1634, 102 * time.Millisecond},
+		{time.Second, 2474, 155 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		offset, at := m.Offset(tt.d)
+		if offset != tt.offset || at != tt.t {
+			t.Errorf("Offset(%s) = %d, %s, want %d, %s", tt.d, offset, at, tt.offset, tt.t)
+		}
+	}
+
+	tag := NewID3v2Tag(3)
+	tag.AddFrame(tag.newFrame("MLLT", m))
+	data, err := tag.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := read.LocationLookupTable(); !reflect.DeepEqual(got, m) {
+		t.Errorf("read %+v", got)
+	}
+}
@@ -0,0 +1,109 @@
+package v2
+
+// MusicBrainzOwner is the UFID owner under which MusicBrainz stores the
+// recording ID.
+const MusicBrainzOwner = "http://musicbrainz.org"
+
+// User defined text frame descriptions used by MusicBrainz Picard.
+//
+// Refer to https://picard-docs.musicbrainz.org/en/appendices/tag_mapping.html
+const (
+	MusicBrainzTrackDescription        = "MusicBrainz Release Track Id"
+	MusicBrainzReleaseDescription      = "MusicBrainz Album Id"
+	MusicBrainzReleaseGroupDescription = "MusicBrainz Release Group Id"
+	MusicBrainzArtistDescription       = "MusicBrainz Artist Id"
+	MusicBrainzAlbumArtistDescription  = "MusicBrainz Album Artist Id"
+)
+
+// MusicBrainzIDs holds the MusicBrainz identifiers of a tag. Tracks may be
+// credited to several artists, hence the lists.
+type MusicBrainzIDs struct {
+	RecordingID    string
+	TrackID        string
+	ReleaseID      string
+	ReleaseGroupID string
+	ArtistIDs      []string
+	AlbumArtistIDs []string
+}
+
+// UniqueFileIdentifier returns the identifier of the UFID (UFI in ID3v2.2)
+// frame with the given owner, or nil.
+func (tag *ID3v2Tag) UniqueFileIdentifier(owner string) []byte {
+	for _, frame := range tag.Frames {
+		if i, ok := frame.Data.(*IdFrame); ok && i.Owner == owner {
+			return i.Identifier
+		}
+	}
+	return nil
+}
+
+// SetUniqueFileIdentifier replaces the UFID (UFI in ID3v2.2) frame with the
+// given owner. A nil identifier removes it.
+func (tag *ID3v2Tag) SetUniqueFileIdentifier(owner string, identifier []byte) {
+	if identifier == nil {
+		tag.RemoveFrame(func(frame *ID3v2Frame) bool {
+			i, ok := frame.Data.(*IdFrame)
+			return ok && i.Owner == owner
+		})
+		return
+	}
+	id := "UFID"
+	if tag.Header.Version == 2 {
+		id = "UFI"
+	}
+	tag.replaceFrame(tag.newFrame(id, &IdFrame{Owner: owner, Identifier: identifier}))
+}
+
+// MusicBrainz returns the MusicBrainz identifiers stored in the UFID and
+// user defined text frames.
+func (tag *ID3v2Tag) MusicBrainz() MusicBrainzIDs {
+	return MusicBrainzIDs{
+		RecordingID:    string(tag.UniqueFileIdentifier(MusicBrainzOwner)),
+		TrackID:        tag.userText(MusicBrainzTrackDescription),
+		ReleaseID:      tag.userText(MusicBrainzReleaseDescription),
+		ReleaseGroupID: tag.userText(MusicBrainzReleaseGroupDescription),
+		ArtistIDs:      tag.musicBrainzIDList(MusicBrainzArtistDescription),
+		AlbumArtistIDs: tag.musicBrainzIDList(MusicBrainzAlbumArtistDescription),
+	}
+}
+
+// SetMusicBrainz writes the MusicBrainz identifiers. Empty identifiers are
+// removed from the tag.
+func (tag *ID3v2Tag) SetMusicBrainz(ids MusicBrainzIDs) {
+	var recording []byte
+	if ids.RecordingID != "" {
+		recording = []byte(ids.RecordingID)
+	}
+	tag.SetUniqueFileIdentifier(MusicBrainzOwner, recording)
+	tag.setUserText(MusicBrainzTrackDescription, ids.TrackID)
+	tag.setUserText(MusicBrainzReleaseDescription, ids.ReleaseID)
+	tag.setUserText(MusicBrainzReleaseGroupDescription, ids.ReleaseGroupID)
+	tag.SetUserTextValues(MusicBrainzArtistDescription, ids.ArtistIDs...)
+	tag.SetUserTextValues(MusicBrainzAlbumArtistDescription, ids.AlbumArtistIDs...)
+}
+
+func (tag *ID3v2Tag) userText(description string) string {
+	values := tag.UserTextValues(description)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (tag *ID3v2Tag) setUserText(description, value string) {
+	if value == "" {
+		tag.SetUserTextValues(description)
+		return
+	}
+	tag.SetUserTextValues(description, value)
+}
+
+// musicBrainzIDList returns a list of IDs, which ID3v2.3 stores joined with
+// "/" as it has no other way to store several values.
+func (tag *ID3v2Tag) musicBrainzIDList(description string) []string {
+	values := tag.UserTextValues(description)
+	if tag.Header.Version >= 4 {
+		return values
+	}
+	return splitSlashValues(values)
+}
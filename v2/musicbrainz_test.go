@@ -0,0 +1,40 @@
+package v2
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestMusicBrainzRoundTrip(t *testing.T) {
+	ids := MusicBrainzIDs{
+		RecordingID:    "b1a9c0e9-d987-4042-ae91-78d6a3267d69",
+		TrackID:        "3f4a3b2e-1c1d-4b8e-9e5b-2d1f0a9c8b7a",
+		ReleaseID:      "9e2b0d4e-7c6f-4a0b-8f1e-3d2c1b0a9f8e",
+		ReleaseGroupID: "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d",
+		ArtistIDs:      []string{"5b11f4ce-a62d-471e-81fc-a69a8278c7da", "83d91898-7763-47d7-b03b-b92132375c47"},
+		AlbumArtistIDs: []string{"5b11f4ce-a62d-471e-81fc-a69a8278c7da"},
+	}
+	for _, version := range []int{3, 4} {
+		tag := NewID3v2Tag(version)
+		tag.SetMusicBrainz(ids)
+		data, err := tag.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		read, err := Read(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := read.MusicBrainz(); !reflect.DeepEqual(got, ids) {
+			t.Errorf("v2.%d: got %+v", version, got)
+		}
+		if got := string(read.UniqueFileIdentifier(MusicBrainzOwner)); got != ids.RecordingID {
+			t.Errorf("v2.%d UFID = %q", version, got)
+		}
+		read.SetMusicBrainz(MusicBrainzIDs{})
+		if len(read.Frames) != 0 {
+			t.Errorf("v2.%d: %d frames left after clearing", version, len(read.Frames))
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package v2
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// PopularimeterFrame holds a POPM (POP in ID3v2.2) frame. Each application
+// keeps its own frame, identified by the email field. Counter is nil when
+// the frame has no play counter.
+//
+// Refer to section 4.18 of http://id3.org/id3v2.3.0
+type PopularimeterFrame struct {
+	Email   string
+	Rating  byte
+	Counter *big.Int
+}
+
+func (p *PopularimeterFrame) String() string {
+	return fmt.Sprintf("%s: %d", p.Email, p.Rating)
+}
+
+func (p *PopularimeterFrame) Keys() []string {
+	return []string{p.Email}
+}
+
+func (p *PopularimeterFrame) Encode(version int) ([]byte, error) {
+	data := append(encodeString(EncodingISO8859_1, p.Email), 0, p.Rating)
+	if p.Counter != nil {
+		data = append(data, encodeCounter(p.Counter)...)
+	}
+	return data, nil
+}
+
+// Stars returns the rating on a 0 to 5 scale using the convention of the
+// application that wrote the frame.
+func (p *PopularimeterFrame) Stars() float64 {
+	return RatingSchemeFor(p.Email).Stars(p.Rating)
+}
+
+// SetStars sets the rating from a 0 to 5 scale using the convention of the
+// application that owns the frame.
+func (p *PopularimeterFrame) SetStars(stars float64) {
+	p.Rating = RatingSchemeFor(p.Email).Rating(stars)
+}
+
+func ParsePopularimeterFrame(data []byte) (ID3v2Framer, error) {
+	email, rest := splitString(EncodingISO8859_1, data)
+	if len(rest) < 1 {
+		return nil, fmt.Errorf("popularimeter frame too short")
+	}
+	p := &PopularimeterFrame{
+		Email:  ISO8859_1ToUTF8(email),
+		Rating: rest[0],
+	}
+	if len(rest) > 1 {
+		p.Counter = new(big.Int).SetBytes(rest[1:])
+	}
+	return p, nil
+}
+
+// PlayCounterFrame holds a PCNT (CNT in ID3v2.2) frame. A nil Counter is
+// zero.
+type PlayCounterFrame struct {
+	Counter *big.Int
+}
+
+func (c *PlayCounterFrame) String() string {
+	if c.Counter == nil {
+		return "0"
+	}
+	return c.Counter.String()
+}
+
+func (c *PlayCounterFrame) Encode(version int) ([]byte, error) {
+	return encodeCounter(c.Counter), nil
+}
+
+func ParsePlayCounterFrame(data []byte) (ID3v2Framer, error) {
+	return &PlayCounterFrame{
+		Counter: new(big.Int).SetBytes(data),
+	}, nil
+}
+
+// encodeCounter writes a counter big endian using at least four bytes, as
+// required for both POPM and PCNT. A nil counter is zero.
+func encodeCounter(counter *big.Int) []byte {
+	var b []byte
+	if counter != nil {
+		b = counter.Bytes()
+	}
+	if len(b) < 4 {
+		b = append(make([]byte, 4-len(b)), b...)
+	}
+	return b
+}
+
+// Emails used by applications in their popularimeter frames.
+const (
+	EmailWindowsMediaPlayer = "Windows Media Player 9 Series"
+	EmailMediaMonkey        = "no@email"
+	EmailFoobar2000         = "foobar2000"
+)
+
+// RatingScheme maps POPM ratings (1 to 255, 0 being unrated) to stars (0 to
+// 5) following an application's convention.
+type RatingScheme struct {
+	Name string
+	// steps holds the rating written for each number of stars. A nil table
+	// maps ratings linearly.
+	steps []ratingStep
+}
+
+type ratingStep struct {
+	stars  float64
+	rating byte
+}
+
+var (
+	// RatingWindowsMediaPlayer is the convention of Windows Media Player,
+	// which only uses whole stars.
+	RatingWindowsMediaPlayer = &RatingScheme{Name: "Windows Media Player", steps: []ratingStep{
+		{1, 1}, {2, 64}, {3, 128}, {4, 196}, {5, 255},
+	}}
+	// RatingMediaMonkey is the convention of MediaMonkey, which uses half
+	// stars.
+	RatingMediaMonkey = &RatingScheme{Name: "MediaMonkey", steps: []ratingStep{
+		{0.5, 13}, {1, 1}, {1.5, 54}, {2, 64}, {2.5, 118},
+		{3, 128}, {3.5, 186}, {4, 196}, {4.5, 242}, {5, 255},
+	}}
+	// RatingFoobar2000 is the convention of foobar2000, which matches the
+	// values of Windows Media Player.
+	RatingFoobar2000 = &RatingScheme{Name: "foobar2000", steps: RatingWindowsMediaPlayer.steps}
+	// RatingLinear maps ratings linearly, in half stars, and is used for
+	// unknown applications.
+	RatingLinear = &RatingScheme{Name: "linear"}
+)
+
+// RatingSchemeFor returns the rating convention of the application using
+// the given popularimeter email.
+func RatingSchemeFor(email string) *RatingScheme {
+	switch email {
+	case EmailWindowsMediaPlayer:
+		return RatingWindowsMediaPlayer
+	case EmailMediaMonkey:
+		return RatingMediaMonkey
+	case EmailFoobar2000:
+		return RatingFoobar2000
+	}
+	return RatingLinear
+}
+
+// Stars converts a rating to stars, picking the closest step of the scheme.
+func (s *RatingScheme) Stars(rating byte) float64 {
+	if rating == 0 {
+		return 0
+	}
+	if s.steps == nil {
+		return math.Max(0.5, math.Round(float64(rating)*10/255)/2)
+	}
+	best := s.steps[0]
+	for _, step := range s.steps {
+		if step.rating == rating {
+			return step.stars
+		}
+		if math.Abs(float64(step.rating)-float64(rating)) < math.Abs(float64(best.rating)-float64(rating)) {
+			best = step
+		}
+	}
+	return best.stars
+}
+
+// Rating converts stars to a rating, rounding to the closest step of the
+// scheme. Zero stars is unrated.
+func (s *RatingScheme) Rating(stars float64) byte {
+	if stars <= 0 {
+		return 0
+	}
+	stars = math.Min(stars, 5)
+	if s.steps == nil {
+		return byte(math.Max(1, math.Round(stars*255/5)))
+	}
+	best := s.steps[0]
+	for _, step := range s.steps {
+		if math.Abs(step.stars-stars) < math.Abs(best.stars-stars) {
+			best = step
+		}
+	}
+	return best.rating
+}
+
+func (tag *ID3v2Tag) popularimeterID() string {
+	if tag.Header.Version == 2 {
+		return "POP"
+	}
+	return "POPM"
+}
+
+func (tag *ID3v2Tag) playCounterID() string {
+	if tag.Header.Version == 2 {
+		return "CNT"
+	}
+	return "PCNT"
+}
+
+// Popularimeters returns the popularimeter frames of every application.
+func (tag *ID3v2Tag) Popularimeters() []*PopularimeterFrame {
+	var popms []*PopularimeterFrame
+	for _, frame := range tag.Frames {
+		if p, ok := frame.Data.(*PopularimeterFrame); ok {
+			popms = append(popms, p)
+		}
+	}
+	return popms
+}
+
+// Popularimeter returns the popularimeter frame with the given email, or nil.
+func (tag *ID3v2Tag) Popularimeter(email string) *PopularimeterFrame {
+	for _, p := range tag.Popularimeters() {
+		if p.Email == email {
+			return p
+		}
+	}
+	return nil
+}
+
+// SetPopularimeter replaces the popularimeter frame with the same email,
+// leaving the frames of other applications untouched.
+func (tag *ID3v2Tag) SetPopularimeter(p *PopularimeterFrame) {
+	tag.replaceFrame(tag.newFrame(tag.popularimeterID(), p))
+}
+
+// PlayCounter returns the play counter, or nil when the tag has none.
+func (tag *ID3v2Tag) PlayCounter() *big.Int {
+	frame := tag.findFrame(tag.playCounterID())
+	if frame == nil {
+		return nil
+	}
+	if c, ok := frame.Data.(*PlayCounterFrame); ok {
+		return c.Counter
+	}
+	return nil
+}
+
+// SetPlayCounter replaces the play counter. A nil counter removes it.
+func (tag *ID3v2Tag) SetPlayCounter(counter *big.Int) {
+	if counter == nil {
+		tag.RemoveFrames(tag.playCounterID())
+		return
+	}
+	tag.replaceFrame(tag.newFrame(tag.playCounterID(), &PlayCounterFrame{Counter: counter}))
+}
@@ -0,0 +1,66 @@
+package v2
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestPopularimeterRoundTrip(t *testing.T) {
+	tag := NewID3v2Tag(3)
+	wmp := &PopularimeterFrame{Email: EmailWindowsMediaPlayer, Counter: big.NewInt(7)}
+	wmp.SetStars(4)
+	tag.SetPopularimeter(wmp)
+	tag.SetPopularimeter(&PopularimeterFrame{Email: EmailMediaMonkey, Rating: 118})
+	tag.SetPlayCounter(new(big.Int).Lsh(big.NewInt(1), 40))
+
+	data, err := tag.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(read.Popularimeters()); n != 2 {
+		t.Fatalf("got %d popularimeters", n)
+	}
+	p := read.Popularimeter(EmailWindowsMediaPlayer)
+	if p.Rating != 196 || p.Stars() != 4 || p.Counter.Int64() != 7 {
+		t.Errorf("WMP popularimeter = %+v", p)
+	}
+	if stars := read.Popularimeter(EmailMediaMonkey).Stars(); stars != 2.5 {
+		t.Errorf("MediaMonkey stars = %v", stars)
+	}
+	if got := read.PlayCounter(); got.Cmp(new(big.Int).Lsh(big.NewInt(1), 40)) != 0 {
+		t.Errorf("play counter = %v", got)
+	}
+}
+
+func TestPlayCounterNil(t *testing.T) {
+	c := &PlayCounterFrame{}
+	if got := c.String(); got != "0" {
+		t.Errorf("String() = %q", got)
+	}
+	data, err := c.Encode(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{0, 0, 0, 0}) {
+		t.Errorf("Encode() = %v", data)
+	}
+}
+
+func TestRatingSchemes(t *testing.T) {
+	for stars := 0.5; stars <= 5; stars += 0.5 {
+		if got := RatingLinear.Stars(RatingLinear.Rating(stars)); got != stars {
+			t.Errorf("linear %v stars read back as %v", stars, got)
+		}
+		if got := RatingMediaMonkey.Stars(RatingMediaMonkey.Rating(stars)); got != stars {
+			t.Errorf("MediaMonkey %v stars read back as %v", stars, got)
+		}
+	}
+	if RatingSchemeFor("unknown@example.com") != RatingLinear {
+		t.Error("unknown email does not use the linear scheme")
+	}
+}
@@ -0,0 +1,288 @@
+package v2
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf16"
+)
+
+// BinaryCodec decodes the binary content of a PRIV or GEOB frame into a Go
+// value and encodes it back. Encode may be nil for read-only decoders.
+type BinaryCodec struct {
+	Decode func(data []byte) (interface{}, error)
+	Encode func(value interface{}) ([]byte, error)
+}
+
+var (
+	codecsMu      sync.RWMutex
+	privateCodecs = make(map[string]BinaryCodec)
+	objectCodecs  = make(map[string]BinaryCodec)
+)
+
+// RegisterPrivateCodec registers the codec used for PRIV frames with the
+// given owner identifier, replacing any previous one.
+func RegisterPrivateCodec(owner string, codec BinaryCodec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	privateCodecs[owner] = codec
+}
+
+// RegisterObjectCodec registers the codec used for GEOB frames with the
+// given content description, replacing any previous one.
+func RegisterObjectCodec(description string, codec BinaryCodec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	objectCodecs[description] = codec
+}
+
+func lookupCodec(codecs map[string]BinaryCodec, key string) (BinaryCodec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[key]
+	return codec, ok
+}
+
+// decodeValue decodes data with the registered codec. Data that the codec
+// rejects is left undecoded so that a single odd frame does not make the
+// whole tag unreadable.
+func decodeValue(codecs map[string]BinaryCodec, key string, data []byte) interface{} {
+	codec, ok := lookupCodec(codecs, key)
+	if !ok || codec.Decode == nil {
+		return nil
+	}
+	value, err := codec.Decode(data)
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+// encodeValue encodes value with the registered codec, falling back to data
+// when there is no value or no encoder.
+func encodeValue(codecs map[string]BinaryCodec, key string, value interface{}, data []byte) ([]byte, error) {
+	if value == nil {
+		return data, nil
+	}
+	codec, ok := lookupCodec(codecs, key)
+	if !ok || codec.Encode == nil {
+		return data, nil
+	}
+	return codec.Encode(value)
+}
+
+// PrivateFrame holds a PRIV frame: an owner identifier and binary data only
+// meaningful to that owner. Value holds the data decoded by the codec
+// registered for the owner, and is encoded back in place of Data when set.
+//
+// Refer to section 4.28 of http://id3.org/id3v2.3.0
+type PrivateFrame struct {
+	Owner string
+	Data  []byte
+	Value interface{}
+}
+
+func (p *PrivateFrame) String() string {
+	if p.Value != nil {
+		return fmt.Sprint(p.Value)
+	}
+	return p.Owner
+}
+
+func (p *PrivateFrame) Keys() []string {
+	return []string{p.Owner + "\x00" + string(p.Data)}
+}
+
+func (p *PrivateFrame) Encode(version int) ([]byte, error) {
+	data, err := encodeValue(privateCodecs, p.Owner, p.Value, p.Data)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(encodeString(EncodingISO8859_1, p.Owner), 0), data...), nil
+}
+
+func ParsePrivateFrame(data []byte) (ID3v2Framer, error) {
+	owner, rest := splitString(EncodingISO8859_1, data)
+	p := &PrivateFrame{
+		Owner: ISO8859_1ToUTF8(owner),
+		Data:  rest,
+	}
+	p.Value = decodeValue(privateCodecs, p.Owner, p.Data)
+	return p, nil
+}
+
+// ObjectFrame holds a general encapsulated object (GEOB, GEO in ID3v2.2)
+// frame. Value holds the object decoded by the codec registered for the
+// description, and is encoded back in place of Object when set.
+//
+// Refer to section 4.16 of http://id3.org/id3v2.3.0
+type ObjectFrame struct {
+	MIMEType    string
+	Filename    string
+	Description string
+	Object      []byte
+	Value       interface{}
+}
+
+func (o *ObjectFrame) String() string {
+	return o.Description
+}
+
+func (o *ObjectFrame) Keys() []string {
+	return []string{o.Description}
+}
+
+func (o *ObjectFrame) Encode(version int) ([]byte, error) {
+	object, err := encodeValue(objectCodecs, o.Description, o.Value, o.Object)
+	if err != nil {
+		return nil, err
+	}
+	encoding := textEncoding(version, o.Filename, o.Description)
+	data := []byte{encoding}
+	data = append(data, encodeString(EncodingISO8859_1, o.MIMEType)...)
+	data = append(data, 0)
+	data = append(data, encodeString(encoding, o.Filename)...)
+	data = append(data, stringTerminator(encoding)...)
+	data = append(data, encodeString(encoding, o.Description)...)
+	data = append(data, stringTerminator(encoding)...)
+	return append(data, object...), nil
+}
+
+func ParseObjectFrame(data []byte) (ID3v2Framer, error) {
+	if len(data) < 1 {
+		return nil, fmt.Errorf("encapsulated object frame too short")
+	}
+	encoding := data[0]
+	mime, rest := splitString(EncodingISO8859_1, data[1:])
+	filename, rest := splitString(encoding, rest)
+	desc, object := splitString(encoding, rest)
+	o := &ObjectFrame{
+		MIMEType: ISO8859_1ToUTF8(mime),
+		Object:   object,
+	}
+	var err error
+	if o.Filename, err = decodeString(encoding, filename); err != nil {
+		return nil, err
+	}
+	if o.Description, err = decodeString(encoding, desc); err != nil {
+		return nil, err
+	}
+	o.Value = decodeValue(objectCodecs, o.Description, o.Object)
+	return o, nil
+}
+
+// Codecs for private data commonly written by Windows Media Player and by
+// HTTP Live Streaming segmenters.
+var (
+	// GUIDCodec handles 16 byte Windows GUIDs, as strings such as
+	// "D1607DBC-E323-4BE2-86A1-48A42A28441E".
+	GUIDCodec = BinaryCodec{Decode: decodeGUID, Encode: encodeGUID}
+	// Uint32Codec handles little endian 32 bit integers, as uint32.
+	Uint32Codec = BinaryCodec{Decode: decodeUint32, Encode: encodeUint32}
+	// UTF16StringCodec handles NUL terminated UTF-16LE strings, as string.
+	UTF16StringCodec = BinaryCodec{Decode: decodeUTF16String, Encode: encodeUTF16String}
+	// TransportStreamTimestampCodec handles the 33 bit MPEG-2 transport
+	// stream timestamp of the first sample of an HLS audio segment, stored
+	// as a big endian 64 bit integer, as uint64.
+	TransportStreamTimestampCodec = BinaryCodec{Decode: decodeTimestamp, Encode: encodeTimestamp}
+)
+
+// TransportStreamTimestampOwner is the PRIV owner of the HLS timestamp.
+const TransportStreamTimestampOwner = "com.apple.streaming.transportStreamTimestamp"
+
+func init() {
+	for _, owner := range []string{
+		"WM/MediaClassPrimaryID",
+		"WM/MediaClassSecondaryID",
+		"WM/WMContentID",
+		"WM/WMCollectionID",
+		"WM/WMCollectionGroupID",
+	} {
+		RegisterPrivateCodec(owner, GUIDCodec)
+	}
+	RegisterPrivateCodec("AverageLevel", Uint32Codec)
+	RegisterPrivateCodec("PeakValue", Uint32Codec)
+	RegisterPrivateCodec("WM/Provider", UTF16StringCodec)
+	RegisterPrivateCodec("WM/UniqueFileIdentifier", UTF16StringCodec)
+	RegisterPrivateCodec(TransportStreamTimestampOwner, TransportStreamTimestampCodec)
+}
+
+func decodeGUID(data []byte) (interface{}, error) {
+	if len(data) != 16 {
+		return nil, fmt.Errorf("invalid GUID length: %d", len(data))
+	}
+	return fmt.Sprintf("%08X-%04X-%04X-%X-%X",
+		binary.LittleEndian.Uint32(data[0:4]),
+		binary.LittleEndian.Uint16(data[4:6]),
+		binary.LittleEndian.Uint16(data[6:8]),
+		data[8:10], data[10:16]), nil
+}
+
+func encodeGUID(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid GUID value: %v", value)
+	}
+	var a uint32
+	var b, c uint16
+	var d, e []byte
+	_, err := fmt.Sscanf(s, "%08X-%04X-%04X-%4X-%12X", &a, &b, &c, &d, &e)
+	if err != nil || len(d) != 2 || len(e) != 6 {
+		return nil, fmt.Errorf("invalid GUID: %s", s)
+	}
+	data := make([]byte, 8, 16)
+	binary.LittleEndian.PutUint32(data[0:4], a)
+	binary.LittleEndian.PutUint16(data[4:6], b)
+	binary.LittleEndian.PutUint16(data[6:8], c)
+	return append(append(data, d...), e...), nil
+}
+
+func decodeUint32(data []byte) (interface{}, error) {
+	if len(data) != 4 {
+		return nil, fmt.Errorf("invalid uint32 length: %d", len(data))
+	}
+	return binary.LittleEndian.Uint32(data), nil
+}
+
+func encodeUint32(value interface{}) ([]byte, error) {
+	v, ok := value.(uint32)
+	if !ok {
+		return nil, fmt.Errorf("invalid uint32 value: %v", value)
+	}
+	return binary.LittleEndian.AppendUint32(nil, v), nil
+}
+
+func decodeUTF16String(data []byte) (interface{}, error) {
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("invalid UTF-16 length: %d", len(data))
+	}
+	return strings.TrimRight(string(utf16.Decode(toUTF16(data, false))), "\u0000"), nil
+}
+
+func encodeUTF16String(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid string value: %v", value)
+	}
+	var data []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		data = append(data, byte(u), byte(u>>8))
+	}
+	return append(data, 0, 0), nil
+}
+
+func decodeTimestamp(data []byte) (interface{}, error) {
+	if len(data) != 8 {
+		return nil, fmt.Errorf("invalid timestamp length: %d", len(data))
+	}
+	return binary.BigEndian.Uint64(data) & (1<<33 - 1), nil
+}
+
+func encodeTimestamp(value interface{}) ([]byte, error) {
+	v, ok := value.(uint64)
+	if !ok {
+		return nil, fmt.Errorf("invalid timestamp value: %v", value)
+	}
+	return binary.BigEndian.AppendUint64(nil, v&(1<<33-1)), nil
+}
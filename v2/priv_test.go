@@ -0,0 +1,75 @@
+package v2
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestPrivateCodecs(t *testing.T) {
+	tests := []struct {
+		owner string
+		value interface{}
+		data  []byte
+	}{
+		{"WM/MediaClassPrimaryID", "D1607DBC-E323-4BE2-86A1-48A42A28441E",
+			[]byte{0xBC, 0x7D, 0x60, 0xD1, 0x23, 0xE3, 0xE2, 0x4B, 0x86, 0xA1, 0x48, 0xA4, 0x2A, 0x28, 0x44, 0x1E}},
+		{"AverageLevel", uint32(0x1234), []byte{0x34, 0x12, 0, 0}},
+		{"WM/Provider", "AMG", []byte{'A', 0, 'M', 0, 'G', 0, 0, 0}},
+		{TransportStreamTimestampOwner, uint64(1 << 32), []byte{0, 0, 0, 1, 0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		raw := append(append([]byte(tt.owner), 0), tt.data...)
+		frame, err := ParsePrivateFrame(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := frame.(*PrivateFrame)
+		if p.Value != tt.value {
+			t.Errorf("%s: decoded %#v, want %#v", tt.owner, p.Value, tt.value)
+		}
+		encoded, err := (&PrivateFrame{Owner: tt.owner, Value: tt.value}).Encode(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(encoded, raw) {
+			t.Errorf("%s: encoded %x, want %x", tt.owner, encoded, raw)
+		}
+	}
+}
+
+func TestPrivateFrameUnknownOwner(t *testing.T) {
+	frame, err := ParsePrivateFrame([]byte("example.com\x00\x01\x02"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := frame.(*PrivateFrame)
+	if p.Owner != "example.com" || p.Value != nil || !bytes.Equal(p.Data, []byte{1, 2}) {
+		t.Errorf("got %+v", p)
+	}
+	// Data that the codec rejects is left undecoded.
+	frame, _ = ParsePrivateFrame([]byte("AverageLevel\x00\x01"))
+	if p := frame.(*PrivateFrame); p.Value != nil {
+		t.Errorf("short AverageLevel decoded as %v", p.Value)
+	}
+}
+
+func TestObjectCodec(t *testing.T) {
+	RegisterObjectCodec("test counter", BinaryCodec{
+		Decode: func(data []byte) (interface{}, error) { return string(data), nil },
+		Encode: func(value interface{}) ([]byte, error) { return []byte(fmt.Sprint(value)), nil },
+	})
+	o := &ObjectFrame{MIMEType: "text/plain", Filename: "n.txt", Description: "test counter", Value: "42"}
+	data, err := o.Encode(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frame, err := ParseObjectFrame(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := frame.(*ObjectFrame)
+	if got.MIMEType != "text/plain" || got.Filename != "n.txt" || got.Value != "42" || string(got.Object) != "42" {
+		t.Errorf("got %+v", got)
+	}
+}
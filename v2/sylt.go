@@ -0,0 +1,230 @@
+package v2
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timestamp formats of synchronised frames.
+const (
+	TimestampMPEGFrames   byte = 0x01
+	TimestampMilliseconds byte = 0x02
+)
+
+// Content types of synchronised lyrics frames.
+const (
+	SyncContentOther byte = iota
+	SyncContentLyrics
+	SyncContentTranscription
+	SyncContentMovement
+	SyncContentEvents
+	SyncContentChord
+	SyncContentTrivia
+	SyncContentWebpageURLs
+	SyncContentImageURLs
+)
+
+// SyncedText is one entry of a synchronised lyrics frame. Timestamp is in the
+// unit given by the frame's timestamp format.
+type SyncedText struct {
+	Text      string
+	Timestamp uint32
+}
+
+// SyncLyricsFrame holds a synchronised lyrics (SYLT, SLT in ID3v2.2) frame.
+//
+// Refer to section 4.10 of http://id3.org/id3v2.3.0
+type SyncLyricsFrame struct {
+	Language        string
+	TimestampFormat byte
+	ContentType     byte
+	Description     string
+	Lines           []SyncedText
+}
+
+func (s *SyncLyricsFrame) String() string {
+	texts := make([]string, len(s.Lines))
+	for i, line := range s.Lines {
+		texts[i] = line.Text
+	}
+	return strings.Join(texts, "\n")
+}
+
+func (s *SyncLyricsFrame) Keys() []string {
+	return []string{s.Language + "\x00" + s.Description}
+}
+
+func (s *SyncLyricsFrame) Encode(version int) ([]byte, error) {
+	texts := []string{s.Description}
+	for _, line := range s.Lines {
+		texts = append(texts, line.Text)
+	}
+	encoding := textEncoding(version, texts...)
+	data := []byte{encoding}
+	data = append(data, encodeLanguage(s.Language)...)
+	data = append(data, s.TimestampFormat, s.ContentType)
+	data = append(data, encodeString(encoding, s.Description)...)
+	data = append(data, stringTerminator(encoding)...)
+	for _, line := range s.Lines {
+		data = append(data, encodeString(encoding, line.Text)...)
+		data = append(data, stringTerminator(encoding)...)
+		t := line.Timestamp
+		data = append(data, byte(t>>24), byte(t>>16), byte(t>>8), byte(t))
+	}
+	return data, nil
+}
+
+func ParseSyncLyricsFrame(data []byte) (ID3v2Framer, error) {
+	if len(data) < 6 {
+		return nil, fmt.Errorf("synchronised lyrics frame too short: %d bytes", len(data))
+	}
+	encoding := data[0]
+	s := &SyncLyricsFrame{
+		Language:        string(data[1:4]),
+		TimestampFormat: data[4],
+		ContentType:     data[5],
+	}
+	desc, rest := splitString(encoding, data[6:])
+	var err error
+	if s.Description, err = decodeString(encoding, desc); err != nil {
+		return nil, err
+	}
+	for len(rest) > 0 {
+		var text []byte
+		text, rest = splitString(encoding, rest)
+		if len(rest) < 4 {
+			return nil, fmt.Errorf("synchronised lyrics frame truncated")
+		}
+		line := SyncedText{
+			Timestamp: uint32(rest[0])<<24 | uint32(rest[1])<<16 | uint32(rest[2])<<8 | uint32(rest[3]),
+		}
+		if line.Text, err = decodeString(encoding, text); err != nil {
+			return nil, err
+		}
+		s.Lines = append(s.Lines, line)
+		rest = rest[4:]
+	}
+	return s, nil
+}
+
+// SyncLyrics returns the synchronised lyrics frames of the tag.
+func (tag *ID3v2Tag) SyncLyrics() []*SyncLyricsFrame {
+	var frames []*SyncLyricsFrame
+	for _, frame := range tag.Frames {
+		if s, ok := frame.Data.(*SyncLyricsFrame); ok {
+			frames = append(frames, s)
+		}
+	}
+	return frames
+}
+
+var (
+	lrcTimestamp = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	lrcTag       = regexp.MustCompile(`^\[([A-Za-z]+):(.*)\]\s*$`)
+)
+
+// ParseLRC reads lyrics in the LRC format into a synchronised lyrics frame
+// with millisecond timestamps. A line may carry several timestamps, lines
+// without a timestamp continue the previous entry, and the [offset:] tag is
+// applied to every timestamp. Other LRC tags are ignored.
+func ParseLRC(r io.Reader) (*SyncLyricsFrame, error) {
+	s := &SyncLyricsFrame{
+		Language:        "XXX",
+		TimestampFormat: TimestampMilliseconds,
+		ContentType:     SyncContentLyrics,
+	}
+	var offset int64
+	var last []int
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if m := lrcTag.FindStringSubmatch(line); m != nil && !lrcTimestamp.MatchString(line) {
+			if strings.EqualFold(m[1], "offset") {
+				v, err := strconv.ParseInt(strings.TrimSpace(m[2]), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid LRC offset: %s", m[2])
+				}
+				offset = v
+			}
+			continue
+		}
+
+		var times []int64
+		for {
+			m := lrcTimestamp.FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+			times = append(times, lrcMilliseconds(m))
+			line = line[len(m[0]):]
+		}
+		if len(times) == 0 {
+			if len(last) > 0 && strings.TrimSpace(line) != "" {
+				for _, i := range last {
+					s.Lines[i].Text += "\n" + line
+				}
+			}
+			continue
+		}
+		last = last[:0]
+		for _, t := range times {
+			last = append(last, len(s.Lines))
+			s.Lines = append(s.Lines, SyncedText{Text: line, Timestamp: uint32(t)})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// A positive offset shows the lyrics sooner.
+	for i := range s.Lines {
+		t := int64(s.Lines[i].Timestamp) - offset
+		if t < 0 {
+			t = 0
+		}
+		s.Lines[i].Timestamp = uint32(t)
+	}
+	sort.SliceStable(s.Lines, func(i, j int) bool {
+		return s.Lines[i].Timestamp < s.Lines[j].Timestamp
+	})
+	return s, nil
+}
+
+func lrcMilliseconds(m []string) int64 {
+	minutes, _ := strconv.ParseInt(m[1], 10, 64)
+	seconds, _ := strconv.ParseInt(m[2], 10, 64)
+	ms := (minutes*60 + seconds) * 1000
+	if m[3] != "" {
+		fraction, _ := strconv.ParseInt(m[3], 10, 64)
+		for i := len(m[3]); i < 3; i++ {
+			fraction *= 10
+		}
+		ms += fraction
+	}
+	return ms
+}
+
+// WriteLRC writes the frame in the LRC format with hundredths of a second.
+// Entries spanning several lines are written with the following lines
+// untimed. Only frames with millisecond timestamps can be written since
+// MPEG frame timestamps depend on the audio.
+func (s *SyncLyricsFrame) WriteLRC(w io.Writer) error {
+	if s.TimestampFormat != TimestampMilliseconds {
+		return fmt.Errorf("unsupported timestamp format for LRC: %d", s.TimestampFormat)
+	}
+	bw := bufio.NewWriter(w)
+	for _, line := range s.Lines {
+		d := time.Duration(line.Timestamp) * time.Millisecond
+		minutes := int(d / time.Minute)
+		seconds := int(d % time.Minute / time.Second)
+		hundredths := int(d % time.Second / (10 * time.Millisecond))
+		fmt.Fprintf(bw, "[%02d:%02d.%02d]%s\n", minutes, seconds, hundredths, line.Text)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,96 @@
+package v2
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLRC(t *testing.T) {
+	lrc := strings.Join([]string{
+		"[ar:Artist]",
+		"[offset:+500]",
+		"[00:12.00]First line",
+		"[00:15.5][01:15.50]Chorus",
+		"  and its second line",
+		"[00:00.25]Early",
+		"",
+	}, "\n")
+	s, err := ParseLRC(strings.NewReader(lrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SyncedText{
+		{Text: "Early", Timestamp: 0},
+		{Text: "First line", Timestamp: 11500},
+		{Text: "Chorus\n  and its second line", Timestamp: 15000},
+		{Text: "Chorus\n  and its second line", Timestamp: 75000},
+	}
+	if !reflect.DeepEqual(s.Lines, want) {
+		t.Errorf("got %+v, want %+v", s.Lines, want)
+	}
+	if s.TimestampFormat != TimestampMilliseconds || s.ContentType != SyncContentLyrics {
+		t.Errorf("format %d, content type %d", s.TimestampFormat, s.ContentType)
+	}
+
+	negative, err := ParseLRC(strings.NewReader("[offset:-250]\n[00:01.00]Late\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := negative.Lines[0].Timestamp; got != 1250 {
+		t.Errorf("negative offset: timestamp %d, want 1250", got)
+	}
+	if _, err := ParseLRC(strings.NewReader("[offset:soon]\n")); err == nil {
+		t.Error("invalid offset accepted")
+	}
+}
+
+func TestWriteLRC(t *testing.T) {
+	s := &SyncLyricsFrame{
+		TimestampFormat: TimestampMilliseconds,
+		Lines:           []SyncedText{{Text: "One", Timestamp: 61230}, {Text: "Two\nmore", Timestamp: 65000}},
+	}
+	var b bytes.Buffer
+	if err := s.WriteLRC(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "[01:01.23]One\n[01:05.00]Two\nmore\n"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+	read, err := ParseLRC(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.Lines, s.Lines) {
+		t.Errorf("read back %+v", read.Lines)
+	}
+	s.TimestampFormat = TimestampMPEGFrames
+	if err := s.WriteLRC(&b); err == nil {
+		t.Error("MPEG frame timestamps written as LRC")
+	}
+}
+
+func TestSyncLyricsFrameRoundTrip(t *testing.T) {
+	for _, version := range []int{2, 3, 4} {
+		s := &SyncLyricsFrame{
+			Language:        "eng",
+			TimestampFormat: TimestampMilliseconds,
+			ContentType:     SyncContentLyrics,
+			Description:     "Ünïcode",
+			Lines:           []SyncedText{{Text: "Hello", Timestamp: 1000}, {Text: "Wörld", Timestamp: 2000}},
+		}
+		data, err := s.Encode(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		frame, err := ParseSyncLyricsFrame(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(frame, s) {
+			t.Errorf("v2.%d: got %+v", version, frame)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package v2
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Track returns the track number and, when present, the total number of
+// tracks from the TRCK (TRK in ID3v2.2) frame.
+func (tag *ID3v2Tag) Track() (num, total int, ok bool) {
+	return tag.numberPair("track")
+}
+
+// SetTrack writes the TRCK (TRK in ID3v2.2) frame as "num" or "num/total".
+// A total of zero is omitted and a num of zero removes the frame.
+func (tag *ID3v2Tag) SetTrack(num, total int) {
+	tag.setNumberPair("track", num, total)
+}
+
+// Disc returns the disc number and, when present, the total number of discs
+// from the TPOS (TPA in ID3v2.2) frame.
+func (tag *ID3v2Tag) Disc() (num, total int, ok bool) {
+	return tag.numberPair("disc")
+}
+
+// SetDisc writes the TPOS (TPA in ID3v2.2) frame as "num" or "num/total".
+// A total of zero is omitted and a num of zero removes the frame.
+func (tag *ID3v2Tag) SetDisc(num, total int) {
+	tag.setNumberPair("disc", num, total)
+}
+
+func (tag *ID3v2Tag) numberPair(field string) (num, total int, ok bool) {
+	frame := tag.findFrame(tag.frameMapping()[field])
+	if frame == nil {
+		return 0, 0, false
+	}
+	return ParseNumberPair(frame.Data.String())
+}
+
+func (tag *ID3v2Tag) setNumberPair(field string, num, total int) {
+	id := tag.frameMapping()[field]
+	if num <= 0 {
+		tag.SetTextValues(id)
+		return
+	}
+	tag.SetTextValues(id, FormatNumberPair(num, total))
+}
+
+// ParseNumberPair parses the "num" or "num/total" form used by the track and
+// part of a set frames. Surrounding spaces, leading zeros and trailing junk
+// after either number are ignored, so " 03 / 12 " and "3a/12b" both parse
+// as 3 of 12. ok is false when no leading number can be found; total is zero
+// when absent or unparsable.
+func ParseNumberPair(s string) (num, total int, ok bool) {
+	numPart, totalPart, _ := strings.Cut(s, "/")
+	num, ok = leadingNumber(numPart)
+	if !ok {
+		return 0, 0, false
+	}
+	total, _ = leadingNumber(totalPart)
+	return num, total, true
+}
+
+// FormatNumberPair is the inverse of ParseNumberPair.
+func FormatNumberPair(num, total int) string {
+	if total > 0 {
+		return strconv.Itoa(num) + "/" + strconv.Itoa(total)
+	}
+	return strconv.Itoa(num)
+}
+
+func leadingNumber(s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[:end])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,44 @@
+package v2
+
+import "testing"
+
+func TestParseNumberPair(t *testing.T) {
+	tests := []struct {
+		s          string
+		num, total int
+		ok         bool
+	}{
+		{"3", 3, 0, true},
+		{"3/12", 3, 12, true},
+		{" 03 / 12 ", 3, 12, true},
+		{"3a/12b", 3, 12, true},
+		{"7/", 7, 0, true},
+		{"/12", 0, 0, false},
+		{"", 0, 0, false},
+		{"junk", 0, 0, false},
+	}
+	for _, tt := range tests {
+		num, total, ok := ParseNumberPair(tt.s)
+		if num != tt.num || total != tt.total || ok != tt.ok {
+			t.Errorf("ParseNumberPair(%q) = %d, %d, %v, want %d, %d, %v", tt.s, num, total, ok, tt.num, tt.total, tt.ok)
+		}
+	}
+}
+
+func TestTrackAndDisc(t *testing.T) {
+	for _, version := range []int{2, 3, 4} {
+		tag := NewID3v2Tag(version)
+		tag.SetTrack(3, 12)
+		tag.SetDisc(1, 0)
+		if num, total, ok := tag.Track(); num != 3 || total != 12 || !ok {
+			t.Errorf("v2.%d Track() = %d, %d, %v", version, num, total, ok)
+		}
+		if num, total, ok := tag.Disc(); num != 1 || total != 0 || !ok {
+			t.Errorf("v2.%d Disc() = %d, %d, %v", version, num, total, ok)
+		}
+		tag.SetTrack(0, 0)
+		if _, _, ok := tag.Track(); ok {
+			t.Errorf("v2.%d: track not removed", version)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package v2
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Channel types of relative volume adjustment frames.
+const (
+	ChannelOther byte = iota
+	ChannelMaster
+	ChannelFrontRight
+	ChannelFrontLeft
+	ChannelBackRight
+	ChannelBackLeft
+	ChannelFrontCentre
+	ChannelBackCentre
+	ChannelSubwoofer
+)
+
+// VolumeChannel is the volume adjustment of one channel in decibels with
+// the channel's peak volume, an unsigned integer of PeakBits bits.
+type VolumeChannel struct {
+	Type       byte
+	Adjustment float64
+	PeakBits   int
+	Peak       uint64
+}
+
+// PeakAmplitude returns the peak as a fraction of full scale, 0 when the
+// channel has no peak.
+func (c VolumeChannel) PeakAmplitude() float64 {
+	if c.PeakBits == 0 {
+		return 0
+	}
+	return float64(c.Peak) / math.Exp2(float64(c.PeakBits-1))
+}
+
+// RelativeVolumeFrame holds a relative volume adjustment frame: RVA2 in
+// ID3v2.4, identified by a string such as "track" or "album", or its
+// predecessor RVAD (RVA in ID3v2.2), which has fixed channels and no
+// identification. RVAD is set for the latter, which is written in the RVAD
+// layout whatever the tag's version; RVA2 frames are written in the RVAD
+// layout in earlier versions.
+//
+// Refer to section 4.11 of http://id3.org/id3v2.4.0-frames and section 4.12
+// of http://id3.org/id3v2.3.0
+type RelativeVolumeFrame struct {
+	Identification string
+	Channels       []VolumeChannel
+	RVAD           bool
+}
+
+func (v *RelativeVolumeFrame) String() string {
+	channels := make([]string, len(v.Channels))
+	for i, c := range v.Channels {
+		channels[i] = fmt.Sprintf("%d: %+.2f dB", c.Type, c.Adjustment)
+	}
+	return strings.Join(channels, ", ")
+}
+
+func (v *RelativeVolumeFrame) Keys() []string {
+	return []string{v.Identification}
+}
+
+func (v *RelativeVolumeFrame) Encode(version int) ([]byte, error) {
+	if v.RVAD || version < 4 {
+		return encodeRVAD(v.Channels), nil
+	}
+	return encodeRVA2(v.Identification, v.Channels), nil
+}
+
+// channel returns the master volume channel, or the average of the front
+// channels when there is none.
+func (v *RelativeVolumeFrame) channel() (VolumeChannel, bool) {
+	var front []VolumeChannel
+	for _, c := range v.Channels {
+		switch c.Type {
+		case ChannelMaster:
+			return c, true
+		case ChannelFrontRight, ChannelFrontLeft:
+			front = append(front, c)
+		}
+	}
+	if len(front) == 0 {
+		return VolumeChannel{}, false
+	}
+	c := front[0]
+	for _, f := range front[1:] {
+		c.Adjustment = (c.Adjustment + f.Adjustment) / 2
+		if f.PeakAmplitude() > c.PeakAmplitude() {
+			c.Peak, c.PeakBits = f.Peak, f.PeakBits
+		}
+	}
+	return c, true
+}
+
+// ParseRelativeVolumeFrame parses an RVA2 frame.
+func ParseRelativeVolumeFrame(data []byte) (ID3v2Framer, error) {
+	id, rest := splitString(EncodingISO8859_1, data)
+	v := &RelativeVolumeFrame{Identification: ISO8859_1ToUTF8(id)}
+	for len(rest) >= 4 {
+		c := VolumeChannel{
+			Type:       rest[0],
+			Adjustment: float64(int16(uint16(rest[1])<<8|uint16(rest[2]))) / 512,
+			PeakBits:   int(rest[3]),
+		}
+		n := (c.PeakBits + 7) / 8
+		rest = rest[4:]
+		if len(rest) < n {
+			return nil, fmt.Errorf("relative volume adjustment frame too short")
+		}
+		for _, b := range rest[:n] {
+			c.Peak = c.Peak<<8 | uint64(b)
+		}
+		rest = rest[n:]
+		v.Channels = append(v.Channels, c)
+	}
+	return v, nil
+}
+
+func encodeRVA2(identification string, channels []VolumeChannel) []byte {
+	data := append(encodeString(EncodingISO8859_1, identification), 0)
+	for _, c := range channels {
+		adjustment := int16(math.Max(math.Min(math.Round(c.Adjustment*512), math.MaxInt16), math.MinInt16))
+		data = append(data, c.Type, byte(uint16(adjustment)>>8), byte(adjustment), byte(c.PeakBits))
+		n := (c.PeakBits + 7) / 8
+		for i := n - 1; i >= 0; i-- {
+			data = append(data, byte(c.Peak>>(8*uint(i))))
+		}
+	}
+	return data
+}
+
+// rvadChannels gives the RVAD channel order, grouped as stored in the frame.
+var rvadChannels = [][]byte{
+	{ChannelFrontRight, ChannelFrontLeft},
+	{ChannelBackRight, ChannelBackLeft},
+	{ChannelFrontCentre},
+	{ChannelSubwoofer},
+}
+
+// ParseID3v23RelativeVolumeFrame parses an RVAD (RVA in ID3v2.2) frame.
+// RVAD does not define a unit for its volume change, so it is read as a
+// fraction of full scale.
+func ParseID3v23RelativeVolumeFrame(data []byte) (ID3v2Framer, error) {
+	if len(data) < 2 || data[1] == 0 {
+		return nil, fmt.Errorf("invalid relative volume adjustment frame")
+	}
+	flags, bits := data[0], int(data[1])
+	n := (bits + 7) / 8
+	rest := data[2:]
+	read := func() uint64 {
+		v := uint64(0)
+		for _, b := range rest[:n] {
+			v = v<<8 | uint64(b)
+		}
+		rest = rest[n:]
+		return v
+	}
+
+	v := &RelativeVolumeFrame{RVAD: true}
+	flag := 0
+	for _, group := range rvadChannels {
+		if len(rest) < 2*n*len(group) {
+			break
+		}
+		start := len(v.Channels)
+		for _, kind := range group {
+			change := float64(read()) / math.Exp2(float64(bits))
+			if flags&(1<<flag) == 0 {
+				change = -change
+			}
+			flag++
+			v.Channels = append(v.Channels, VolumeChannel{
+				Type:       kind,
+				Adjustment: 20 * math.Log10(1+change),
+				PeakBits:   bits,
+			})
+		}
+		for i := range group {
+			v.Channels[start+i].Peak = read()
+		}
+	}
+	return v, nil
+}
+
+// encodeRVAD writes channels as an RVAD frame, using 16 bits per value. A
+// master volume channel applies to both front channels.
+func encodeRVAD(channels []VolumeChannel) []byte {
+	byKind := make(map[byte]VolumeChannel)
+	for _, c := range channels {
+		byKind[c.Type] = c
+	}
+	if master, ok := byKind[ChannelMaster]; ok {
+		for _, kind := range rvadChannels[0] {
+			if _, ok := byKind[kind]; !ok {
+				byKind[kind] = master
+			}
+		}
+	}
+
+	groups := 1
+	for i, group := range rvadChannels {
+		for _, kind := range group {
+			if _, ok := byKind[kind]; ok {
+				groups = i + 1
+			}
+		}
+	}
+	data := []byte{0, 16}
+	flag := 0
+	for _, group := range rvadChannels[:groups] {
+		var peaks []byte
+		for _, kind := range group {
+			c := byKind[kind]
+			change := math.Pow(10, c.Adjustment/20) - 1
+			if change >= 0 {
+				data[0] |= 1 << flag
+			}
+			flag++
+			v := math.Min(math.Round(math.Abs(change)*65536), 65535)
+			data = append(data, byte(uint16(v)>>8), byte(uint16(v)))
+			peak := scalePeak(c.Peak, c.PeakBits, 16)
+			peaks = append(peaks, byte(peak>>8), byte(peak))
+		}
+		data = append(data, peaks...)
+	}
+	return data
+}
+
+// scalePeak rescales a peak value from one bit width to another.
+func scalePeak(peak uint64, from, to int) uint64 {
+	if from > to {
+		return peak >> uint(from-to)
+	}
+	return peak << uint(to-from)
+}
+
+// Gain is a ReplayGain adjustment in decibels with the peak amplitude as a
+// fraction of full scale, 0 when unknown.
+type Gain struct {
+	Adjustment float64
+	Peak       float64
+}
+
+// ReplayGain holds the track and album gains of a tag, nil when absent.
+type ReplayGain struct {
+	Track *Gain
+	Album *Gain
+}
+
+// User defined text frame descriptions of the ReplayGain convention, as
+// written by foobar2000, Picard and most taggers.
+const (
+	ReplayGainTrackGainDescription = "REPLAYGAIN_TRACK_GAIN"
+	ReplayGainTrackPeakDescription = "REPLAYGAIN_TRACK_PEAK"
+	ReplayGainAlbumGainDescription = "REPLAYGAIN_ALBUM_GAIN"
+	ReplayGainAlbumPeakDescription = "REPLAYGAIN_ALBUM_PEAK"
+)
+
+// iTunesNormalizationDescription is the description of the comment in
+// which iTunes stores its Sound Check volume normalisation.
+const iTunesNormalizationDescription = "iTunNORM"
+
+// ReplayGain returns the gains stored in the tag. Each gain is taken from
+// the first of these places holding it: the REPLAYGAIN_* user defined text
+// frames, the RVA2 frames identified as "track" or "album", and for the
+// track gain the iTunes iTunNORM comment, then the RVAD frame.
+func (tag *ID3v2Tag) ReplayGain() ReplayGain {
+	rg := ReplayGain{
+		Track: tag.userTextGain(ReplayGainTrackGainDescription, ReplayGainTrackPeakDescription),
+		Album: tag.userTextGain(ReplayGainAlbumGainDescription, ReplayGainAlbumPeakDescription),
+	}
+	for _, frame := range tag.Frames {
+		v, ok := frame.Data.(*RelativeVolumeFrame)
+		if !ok {
+			continue
+		}
+		c, ok := v.channel()
+		if !ok {
+			continue
+		}
+		g := &Gain{Adjustment: c.Adjustment, Peak: c.PeakAmplitude()}
+		switch {
+		case strings.EqualFold(v.Identification, "album"):
+			if rg.Album == nil {
+				rg.Album = g
+			}
+		case strings.EqualFold(v.Identification, "track"):
+			if rg.Track == nil {
+				rg.Track = g
+			}
+		}
+	}
+	if rg.Track == nil {
+		rg.Track = tag.iTunesGain()
+	}
+	if rg.Track == nil {
+		for _, frame := range tag.Frames {
+			if v, ok := frame.Data.(*RelativeVolumeFrame); ok && (frame.Id == "RVAD" || frame.Id == "RVA") {
+				if c, ok := v.channel(); ok {
+					rg.Track = &Gain{Adjustment: c.Adjustment, Peak: c.PeakAmplitude()}
+				}
+			}
+		}
+	}
+	return rg
+}
+
+// SetReplayGain writes the gains as REPLAYGAIN_* user defined text frames,
+// and for ID3v2.4 also as RVA2 frames. Nil gains are removed.
+func (tag *ID3v2Tag) SetReplayGain(rg ReplayGain) {
+	tag.setUserTextGain(ReplayGainTrackGainDescription, ReplayGainTrackPeakDescription, rg.Track)
+	tag.setUserTextGain(ReplayGainAlbumGainDescription, ReplayGainAlbumPeakDescription, rg.Album)
+	if tag.Header.Version < 4 {
+		return
+	}
+	for _, identification := range []string{"track", "album"} {
+		g := rg.Track
+		if identification == "album" {
+			g = rg.Album
+		}
+		tag.RemoveFrame(func(frame *ID3v2Frame) bool {
+			v, ok := frame.Data.(*RelativeVolumeFrame)
+			return ok && strings.EqualFold(v.Identification, identification)
+		})
+		if g == nil {
+			continue
+		}
+		peak := math.Min(math.Round(g.Peak*32768), 65535)
+		tag.Frames = append(tag.Frames, tag.newFrame("RVA2", &RelativeVolumeFrame{
+			Identification: identification,
+			Channels: []VolumeChannel{{
+				Type:       ChannelMaster,
+				Adjustment: g.Adjustment,
+				PeakBits:   16,
+				Peak:       uint64(peak),
+			}},
+		}))
+	}
+}
+
+// userTextGain reads a gain from user defined text frames. Descriptions are
+// matched regardless of case as some taggers write them in lower case.
+func (tag *ID3v2Tag) userTextGain(gainDescription, peakDescription string) *Gain {
+	gain, ok := tag.userTextFold(gainDescription)
+	if !ok {
+		return nil
+	}
+	adjustment, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(gain), "dB")), 64)
+	if err != nil {
+		return nil
+	}
+	g := &Gain{Adjustment: adjustment}
+	if peak, ok := tag.userTextFold(peakDescription); ok {
+		g.Peak, _ = strconv.ParseFloat(strings.TrimSpace(peak), 64)
+	}
+	return g
+}
+
+func (tag *ID3v2Tag) userTextFold(description string) (string, bool) {
+	for _, frame := range tag.Frames {
+		if d, ok := frame.Data.(*DescTextFrame); ok && frame.Id == tag.userTextID() &&
+			strings.EqualFold(d.Description, description) && len(d.Values) > 0 {
+			return d.Values[0], true
+		}
+	}
+	return "", false
+}
+
+func (tag *ID3v2Tag) setUserTextGain(gainDescription, peakDescription string, g *Gain) {
+	for _, description := range []string{gainDescription, peakDescription} {
+		tag.RemoveFrame(func(frame *ID3v2Frame) bool {
+			d, ok := frame.Data.(*DescTextFrame)
+			return ok && frame.Id == tag.userTextID() && strings.EqualFold(d.Description, description)
+		})
+	}
+	if g == nil {
+		return
+	}
+	tag.SetUserTextValues(gainDescription, fmt.Sprintf("%+.2f dB", g.Adjustment))
+	if g.Peak != 0 {
+		tag.SetUserTextValues(peakDescription, strconv.FormatFloat(g.Peak, 'f', 6, 64))
+	}
+}
+
+// iTunesGain reads the iTunes Sound Check comment, ten hexadecimal values
+// of which the first two are the power ratios of the left and right channel
+// adjustments scaled by 1000, and the seventh and eighth their peaks with
+// 32768 as full scale.
+func (tag *ID3v2Tag) iTunesGain() *Gain {
+	for _, frame := range tag.Frames {
+		u, ok := frame.Data.(*UnsynchTextFrame)
+		if !ok || (frame.Id != "COMM" && frame.Id != "COM") || u.Description != iTunesNormalizationDescription {
+			continue
+		}
+		fields := strings.Fields(u.Text)
+		if len(fields) < 8 {
+			return nil
+		}
+		var values [8]uint64
+		for i := range values {
+			v, err := strconv.ParseUint(fields[i], 16, 32)
+			if err != nil {
+				return nil
+			}
+			values[i] = v
+		}
+		volume := math.Max(float64(values[0]), float64(values[1]))
+		if volume == 0 {
+			return nil
+		}
+		return &Gain{
+			Adjustment: -10 * math.Log10(volume/1000),
+			Peak:       math.Max(float64(values[6]), float64(values[7])) / 32768,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package v2
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestRelativeVolumeRoundTrip(t *testing.T) {
+	rvad := &RelativeVolumeFrame{
+		Channels: []VolumeChannel{
+			{Type: ChannelFrontRight, Adjustment: -3, PeakBits: 16, Peak: 30000},
+			{Type: ChannelFrontLeft, Adjustment: 2, PeakBits: 16, Peak: 20000},
+		},
+		RVAD: true,
+	}
+	rva2 := &RelativeVolumeFrame{
+		Identification: "album",
+		Channels:       []VolumeChannel{{Type: ChannelMaster, Adjustment: -6.5, PeakBits: 24, Peak: 1 << 22}},
+	}
+	// RVAD is kept in its own layout in an ID3v2.4 tag.
+	tag := NewID3v2Tag(4)
+	for id, v := range map[string]*RelativeVolumeFrame{"RVAD": rvad, "RVA2": rva2} {
+		if err := tag.AddFrame(tag.newFrame(id, v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := tag.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]*RelativeVolumeFrame{"RVAD": rvad, "RVA2": rva2} {
+		frame := read.findFrame(id)
+		if frame == nil {
+			t.Fatalf("no %s frame", id)
+		}
+		got, ok := frame.Data.(*RelativeVolumeFrame)
+		if !ok {
+			t.Fatalf("%s = %#v", id, frame.Data)
+		}
+		if got.RVAD != want.RVAD || got.Identification != want.Identification || len(got.Channels) != len(want.Channels) {
+			t.Fatalf("%s = %+v, want %+v", id, got, want)
+		}
+		for i, c := range got.Channels {
+			w := want.Channels[i]
+			if c.Type != w.Type || math.Abs(c.Adjustment-w.Adjustment) > 0.01 || c.Peak != w.Peak {
+				t.Errorf("%s channel %d = %+v, want %+v", id, i, c, w)
+			}
+		}
+	}
+}
+
+func TestReplayGain(t *testing.T) {
+	rg := ReplayGain{
+		Track: &Gain{Adjustment: -7.25, Peak: 0.988},
+		Album: &Gain{Adjustment: -6.5, Peak: 1},
+	}
+	for _, version := range []int{3, 4} {
+		tag := NewID3v2Tag(version)
+		tag.SetReplayGain(rg)
+		data, err := tag.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		read, err := Read(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := read.ReplayGain()
+		if got.Track == nil || got.Album == nil || *got.Track != *rg.Track || *got.Album != *rg.Album {
+			t.Errorf("v2.%d: got %+v", version, got)
+		}
+		if n := len(read.Frames); version == 4 && n != 6 {
+			t.Errorf("v2.4: got %d frames, want 4 TXXX and 2 RVA2", n)
+		}
+
+		// The RVA2 frames are read when the user text frames are missing.
+		read.RemoveFrames(read.userTextID())
+		got = read.ReplayGain()
+		if version == 4 && (got.Track == nil || math.Abs(got.Track.Adjustment-rg.Track.Adjustment) > 0.01) {
+			t.Errorf("v2.4 RVA2 track gain = %+v", got.Track)
+		}
+
+		read.SetReplayGain(ReplayGain{})
+		if got := read.ReplayGain(); got.Track != nil || got.Album != nil {
+			t.Errorf("v2.%d: gains not removed: %+v", version, got)
+		}
+	}
+}
+
+func TestITunesGain(t *testing.T) {
+	tag := NewID3v2Tag(3)
+	tag.Frames = append(tag.Frames, tag.newFrame("COMM", &UnsynchTextFrame{
+		Language:    "eng",
+		Description: iTunesNormalizationDescription,
+		Text:        " 000003E8 000003E8 00000000 00000000 00000000 00000000 00004000 00002000 00000000 00000000",
+	}))
+	g := tag.ReplayGain().Track
+	if g == nil || g.Adjustment != 0 || g.Peak != 0.5 {
+		t.Errorf("got %+v", g)
+	}
+}
@@ -0,0 +1,95 @@
+package v2
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// ID3v2FrameEncoder is implemented by frame data that can be serialized back
+// into the body of a frame for the given major version. Frames whose data
+// does not implement it are written from their Raw bytes.
+type ID3v2FrameEncoder interface {
+	Encode(version int) ([]byte, error)
+}
+
+// NewID3v2Tag creates an empty tag of the given major version (2, 3 or 4).
+func NewID3v2Tag(version int) *ID3v2Tag {
+	return &ID3v2Tag{
+		Header: &ID3v2Header{
+			Version: version,
+		},
+	}
+}
+
+// Bytes serializes the tag, header included. Header flags are not written
+// since neither unsynchronization nor extended headers are produced.
+func (tag *ID3v2Tag) Bytes() ([]byte, error) {
+	version := tag.Header.Version
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("unsupported ID3v2 version: %d", version)
+	}
+	var body bytes.Buffer
+	for _, frame := range tag.Frames {
+		data, err := encodeFrame(frame, version)
+		if err != nil {
+			return nil, err
+		}
+		body.Write(data)
+	}
+	if body.Len() >= 1<<28 {
+		return nil, fmt.Errorf("tag too large: %d bytes", body.Len())
+	}
+
+	buf := make([]byte, 0, 10+body.Len())
+	buf = append(buf, 'I', 'D', '3', byte(version), byte(tag.Header.Revision), 0)
+	buf = append(buf, encodeSize(int32(body.Len()), 4)...)
+	return append(buf, body.Bytes()...), nil
+}
+
+// Write serializes the tag to w.
+func Write(w io.Writer, tag *ID3v2Tag) error {
+	data, err := tag.Bytes()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// encodeFrameData returns the body of a frame for the given version.
+func encodeFrameData(frame *ID3v2Frame, version int) ([]byte, error) {
+	if encoder, ok := frame.Data.(ID3v2FrameEncoder); ok {
+		return encoder.Encode(version)
+	}
+	return frame.Raw, nil
+}
+
+// encodeFrame returns a frame, header included, for the given version.
+func encodeFrame(frame *ID3v2Frame, version int) ([]byte, error) {
+	data, err := encodeFrameData(frame, version)
+	if err != nil {
+		return nil, err
+	}
+	idLen := 4
+	if version == 2 {
+		idLen = 3
+	}
+	if len(frame.Id) != idLen {
+		return nil, fmt.Errorf("invalid frame id for ID3v2.%d: %s", version, frame.Id)
+	}
+
+	buf := make([]byte, 0, 10+len(data))
+	buf = append(buf, frame.Id...)
+	switch version {
+	case 2:
+		buf = append(buf, EncodeID3v22FrameSize(len(data))...)
+	case 3:
+		buf = append(buf, EncodeID3v23FrameSize(len(data))...)
+		buf = append(buf, 0, 0)
+	default:
+		buf = append(buf, EncodeID3v24FrameSize(len(data))...)
+		buf = append(buf, 0, 0)
+	}
+	return append(buf, data...), nil
+}